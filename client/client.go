@@ -0,0 +1,177 @@
+// Package client is a Go SDK for the direktiv ingress API. A Client wraps a
+// single gRPC connection that is reused by every call, and each method takes
+// a context so that callers control cancellation.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/vorteil/direktiv/pkg/ingress"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// DefaultTimeout is the deadline applied to each call unless configured
+// otherwise.
+const DefaultTimeout = time.Second * 3
+
+// Config describes how to connect to a direktiv server.
+type Config struct {
+	// Address is the host and port of the gRPC ingress.
+	Address string
+	// Timeout is the deadline applied to each call, zero disables it.
+	Timeout time.Duration
+
+	// TLS enables TLS, which is implied by any of the other TLS settings.
+	TLS                bool
+	CACert             string
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+
+	// Token is sent with every call if set, in the metadata key TokenHeader
+	// and prefixed by TokenScheme. See newTokenCredentials for the defaults.
+	Token       string
+	TokenHeader string
+	TokenScheme string
+
+	// Retry is applied to idempotent calls, DefaultRetryPolicy is used if
+	// it is nil.
+	Retry *RetryPolicy
+}
+
+// Client wraps a single connection to a direktiv server that is reused by
+// every call made through it. Each call gets its own context, so closing one
+// call does not affect the others.
+type Client struct {
+	conn    *grpc.ClientConn
+	ingress ingress.DirektivIngressClient
+
+	// Timeout is the deadline applied to each call, zero disables it.
+	Timeout time.Duration
+}
+
+// New returns a client using conn, applying the timeout to each call.
+func New(conn *grpc.ClientConn, timeout time.Duration) *Client {
+	return &Client{
+		conn:    conn,
+		ingress: ingress.NewDirektivIngressClient(conn),
+		Timeout: timeout,
+	}
+}
+
+// Dial opens a connection to the direktiv server described by cfg.
+func Dial(cfg *Config) (*Client, error) {
+	opts, err := DialOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(cfg.Address, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(conn, cfg.Timeout), nil
+}
+
+// DialOptions returns the transport, authentication and retry options needed
+// to connect to the direktiv server described by cfg.
+func DialOptions(cfg *Config) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if cfg.UsesTLS() {
+		tlsConfig, err := TLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	if cfg.Token != "" {
+		if !cfg.UsesTLS() {
+			return nil, fmt.Errorf("a token is only sent over TLS, enable TLS to use it")
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(newTokenCredentials(cfg.Token, cfg.TokenHeader, cfg.TokenScheme)))
+	}
+
+	retry := cfg.Retry
+	if retry == nil {
+		retry = DefaultRetryPolicy()
+	}
+
+	if retry.MaxAttempts > 1 {
+		opts = append(opts, grpc.WithUnaryInterceptor(retry.UnaryInterceptor()))
+	}
+
+	return opts, nil
+}
+
+// UsesTLS reports whether connections to the server use TLS, which is
+// implied by any of the TLS settings.
+func (cfg *Config) UsesTLS() bool {
+	return cfg.TLS || cfg.CACert != "" || cfg.ClientCert != "" || cfg.ClientKey != "" || cfg.InsecureSkipVerify
+}
+
+// TLSConfig builds the TLS configuration for cfg, loading the CA certificate
+// and client key pair from disk if they are set.
+func TLSConfig(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CACert != "" {
+		b, err := ioutil.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in '%s'", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("client-cert and client-key must be provided together")
+		}
+
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Ingress returns the underlying gRPC client for calls not covered by the
+// SDK.
+func (c *Client) Ingress() ingress.DirektivIngressClient {
+	return c.ingress
+}
+
+// Close closes the underlying connection. The client must not be used
+// afterwards.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// context returns a context for a single call derived from ctx, with the
+// client's timeout applied.
+func (c *Client) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testPKI is a self-signed CA with a server and client certificate issued by
+// it, written to PEM files in a temporary directory.
+type testPKI struct {
+	caFile     string
+	serverCert tls.Certificate
+	clientCert string
+	clientKey  string
+	pool       *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "direkcli test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := func(serial int64, usage x509.ExtKeyUsage, name string) (certPEM, keyPEM []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	write := func(name string, b []byte) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	p := &testPKI{
+		caFile: write("ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+		pool:   x509.NewCertPool(),
+	}
+	p.pool.AddCert(ca)
+
+	serverPEM, serverKey := issue(2, x509.ExtKeyUsageServerAuth, "127.0.0.1")
+	p.serverCert, err = tls.X509KeyPair(serverPEM, serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientPEM, clientKey := issue(3, x509.ExtKeyUsageClientAuth, "client")
+	p.clientCert = write("client.pem", clientPEM)
+	p.clientKey = write("client-key.pem", clientKey)
+
+	return p
+}
+
+// serve starts a gRPC health server behind TLS, requiring a client
+// certificate if mutual is set, and returns its address.
+func (p *testPKI) serve(t *testing.T, mutual bool) string {
+	t.Helper()
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{p.serverCert},
+	}
+	if mutual {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = p.pool
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+// check dials cfg with client.New and makes a single health check.
+func check(cfg *Config) error {
+	cfg.Retry = &RetryPolicy{MaxAttempts: 1}
+
+	opts, err := DialOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.Dial(cfg.Address, opts...)
+	if err != nil {
+		return err
+	}
+
+	c := New(conn, 2*time.Second)
+	defer c.Close()
+
+	ctx, cancel := c.context(context.Background())
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	addr := pki.serve(t, false)
+	mtlsAddr := pki.serve(t, true)
+
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{"ca cert", Config{Address: addr, CACert: pki.caFile}, true},
+		{"unknown ca", Config{Address: addr, TLS: true}, false},
+		{"insecure", Config{Address: addr}, false},
+		{"skip verify", Config{Address: addr, InsecureSkipVerify: true}, true},
+		{"mutual tls", Config{Address: mtlsAddr, CACert: pki.caFile, ClientCert: pki.clientCert, ClientKey: pki.clientKey}, true},
+		{"mutual tls without client cert", Config{Address: mtlsAddr, CACert: pki.caFile}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := check(&tt.cfg)
+			if tt.ok && err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestClientKeyWithoutCertImpliesTLS(t *testing.T) {
+	_, err := DialOptions(&Config{Address: "127.0.0.1:1", ClientKey: "key.pem"})
+	if err == nil {
+		t.Fatal("expected an error for --client-key without --client-cert")
+	}
+}
+
+func TestTokenRequiresTLS(t *testing.T) {
+	_, err := DialOptions(&Config{Address: "127.0.0.1:1", Token: "secret"})
+	if err == nil {
+		t.Fatal("expected an error for a token without TLS")
+	}
+
+	_, err = DialOptions(&Config{Address: "127.0.0.1:1", Token: "secret", TLS: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTokenCredentials(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		scheme string
+		key    string
+		value  string
+	}{
+		{"default", "", "", "authorization", "Bearer secret"},
+		{"scheme", "", "Token", "authorization", "Token secret"},
+		{"api key", "X-API-Key", "", "x-api-key", "secret"},
+		{"api key with scheme", "x-api-key", "Key", "x-api-key", "Key secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := newTokenCredentials("secret", tt.header, tt.scheme).GetRequestMetadata(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(md) != 1 || md[tt.key] != tt.value {
+				t.Fatalf("got %v, want %s: %s", md, tt.key, tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultTokenHeader is the metadata key the token is sent in unless
+// configured otherwise.
+const DefaultTokenHeader = "authorization"
+
+// DefaultTokenScheme prefixes the token when it is sent in the
+// authorization metadata without a configured scheme.
+const DefaultTokenScheme = "Bearer"
+
+// tokenCredentials attaches a token to the metadata of every call, as
+// "<header>: <scheme> <token>", or "<header>: <token>" without a scheme.
+type tokenCredentials struct {
+	token  string
+	header string
+	scheme string
+}
+
+// newTokenCredentials returns credentials sending the token in header,
+// prefixed by scheme. An empty header selects DefaultTokenHeader, and an
+// empty scheme selects DefaultTokenScheme for that header only, so that API
+// keys in other headers are sent as they are.
+func newTokenCredentials(token, header, scheme string) *tokenCredentials {
+	header = strings.ToLower(header)
+	if header == "" {
+		header = DefaultTokenHeader
+	}
+	if scheme == "" && header == DefaultTokenHeader {
+		scheme = DefaultTokenScheme
+	}
+
+	return &tokenCredentials{
+		token:  token,
+		header: header,
+		scheme: scheme,
+	}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	value := c.token
+	if c.scheme != "" {
+		value = c.scheme + " " + c.token
+	}
+
+	return map[string]string{
+		c.header: value,
+	}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. Tokens
+// are never sent over plaintext connections.
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors that an *Error matches with errors.Is, depending on its status code.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrUnavailable      = errors.New("unavailable")
+	ErrInvalidArgument  = errors.New("invalid argument")
+)
+
+// kinds maps status codes to the errors they match.
+var kinds = map[codes.Code]error{
+	codes.NotFound:           ErrNotFound,
+	codes.AlreadyExists:      ErrAlreadyExists,
+	codes.PermissionDenied:   ErrPermissionDenied,
+	codes.Unauthenticated:    ErrPermissionDenied,
+	codes.Unavailable:        ErrUnavailable,
+	codes.DeadlineExceeded:   ErrUnavailable,
+	codes.InvalidArgument:    ErrInvalidArgument,
+	codes.FailedPrecondition: ErrInvalidArgument,
+}
+
+// Error is returned when the direktiv server rejects a call. It keeps the
+// gRPC status so that callers can inspect the code, and matches one of the
+// Err* values with errors.Is, e.g. errors.Is(err, client.ErrNotFound).
+type Error struct {
+	status *status.Status
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("[%v] %v", e.status.Code(), e.status.Message())
+}
+
+// Is reports whether target is the Err* value matching the status code.
+func (e *Error) Is(target error) bool {
+	kind, ok := kinds[e.status.Code()]
+	return ok && kind == target
+}
+
+// Code returns the gRPC status code of the error.
+func (e *Error) Code() codes.Code {
+	return e.status.Code()
+}
+
+// Message returns the message sent by the server.
+func (e *Error) Message() string {
+	return e.status.Message()
+}
+
+// GRPCStatus returns the status the error was created from, so that
+// status.FromError and status.Code work on it.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// wrapError converts an error returned by a gRPC call into an *Error.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{status: status.Convert(err)}
+}
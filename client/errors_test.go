@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorIs(t *testing.T) {
+	kinds := []error{ErrNotFound, ErrAlreadyExists, ErrPermissionDenied, ErrUnavailable, ErrInvalidArgument}
+
+	tests := []struct {
+		code codes.Code
+		want error
+	}{
+		{code: codes.NotFound, want: ErrNotFound},
+		{code: codes.AlreadyExists, want: ErrAlreadyExists},
+		{code: codes.PermissionDenied, want: ErrPermissionDenied},
+		{code: codes.Unauthenticated, want: ErrPermissionDenied},
+		{code: codes.Unavailable, want: ErrUnavailable},
+		{code: codes.DeadlineExceeded, want: ErrUnavailable},
+		{code: codes.InvalidArgument, want: ErrInvalidArgument},
+		{code: codes.FailedPrecondition, want: ErrInvalidArgument},
+		{code: codes.Internal},
+		{code: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := wrapError(status.Error(tt.code, "message"))
+
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, kind, got)
+				}
+			}
+
+			// the kind survives being wrapped again
+			if tt.want != nil && !errors.Is(fmt.Errorf("doing something: %w", err), tt.want) {
+				t.Errorf("wrapped %v does not match %v", err, tt.want)
+			}
+
+			var e *Error
+			if !errors.As(err, &e) || e.Code() != tt.code || e.Message() != "message" {
+				t.Fatalf("got %#v, want an *Error with code %v", err, tt.code)
+			}
+			if status.Code(err) != tt.code {
+				t.Fatalf("got status code %v, want %v", status.Code(err), tt.code)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	if err := wrapError(nil); err != nil {
+		t.Fatalf("got %v, want nil", err)
+	}
+
+	// errors that are not statuses are unknown, and match no kind
+	err := wrapError(context.Canceled)
+	if status.Code(err) != codes.Unknown {
+		t.Fatalf("got status code %v, want %v", status.Code(err), codes.Unknown)
+	}
+	if errors.Is(err, ErrUnavailable) {
+		t.Fatalf("%v matches %v", err, ErrUnavailable)
+	}
+}
@@ -0,0 +1,384 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vorteil/direktiv/pkg/ingress"
+)
+
+// Statuses of a workflow instance.
+const (
+	StatusPending   = "pending"
+	StatusComplete  = "complete"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+	StatusCrashed   = "crashed"
+)
+
+// Statuses lists every status an instance can have.
+var Statuses = []string{StatusPending, StatusComplete, StatusFailed, StatusCancelled, StatusCrashed}
+
+// Instance is a single execution of a workflow. Only ID, Status and
+// BeginTime are populated by ListInstances.
+type Instance struct {
+	ID           string     `json:"id"`
+	Status       string     `json:"status"`
+	InvokedBy    string     `json:"invokedBy,omitempty"`
+	Revision     int32      `json:"revision,omitempty"`
+	BeginTime    time.Time  `json:"beginTime"`
+	EndTime      *time.Time `json:"endTime,omitempty"`
+	Flow         []string   `json:"flow,omitempty"`
+	Input        []byte     `json:"input,omitempty"`
+	Output       []byte     `json:"output,omitempty"`
+	ErrorCode    string     `json:"errorCode,omitempty"`
+	ErrorMessage string     `json:"errorMessage,omitempty"`
+}
+
+// Finished reports whether the instance has stopped running.
+func (in *Instance) Finished() bool {
+	return in.Status != StatusPending
+}
+
+// Workflow returns the ID of the workflow that the instance is running,
+// parsed from the instance ID.
+func (in *Instance) Workflow() string {
+	_, wf, _ := ParseInstanceID(in.ID)
+	return wf
+}
+
+// InstanceFilter selects instances by status, workflow and begin time. Zero
+// valued fields match every instance.
+type InstanceFilter struct {
+	Statuses []string
+	Workflow string
+	// Since and Until bound the begin time of the instance.
+	Since time.Time
+	Until time.Time
+}
+
+// IsZero reports whether the filter matches every instance.
+func (f *InstanceFilter) IsZero() bool {
+	return len(f.Statuses) == 0 && f.Workflow == "" && f.Since.IsZero() && f.Until.IsZero()
+}
+
+// Match reports whether the instance is selected by the filter.
+func (f *InstanceFilter) Match(in *Instance) bool {
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, s := range f.Statuses {
+			if s == in.Status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Workflow != "" && f.Workflow != in.Workflow() {
+		return false
+	}
+
+	if !f.Since.IsZero() && in.BeginTime.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && !in.BeginTime.Before(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// ParseInstanceID splits an instance ID of the form
+// 'NAMESPACE/WORKFLOW/SUFFIX' into the namespace and workflow ID.
+func ParseInstanceID(id string) (namespace, workflow string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("%w: malformed instance ID '%s', expected NAMESPACE/WORKFLOW/ID", ErrInvalidArgument, id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// LogEntry is a single line logged by a workflow instance.
+type LogEntry struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Message   string            `json:"message"`
+	Context   map[string]string `json:"context,omitempty"`
+}
+
+// ListInstances returns the workflow instances in a namespace.
+func (c *Client) ListInstances(ctx context.Context, namespace string) ([]*Instance, error) {
+	return c.listInstances(ctx, namespace, 0, 0)
+}
+
+// ListInstancesPage returns up to limit workflow instances in a namespace,
+// starting at offset.
+func (c *Client) ListInstancesPage(ctx context.Context, namespace string, offset, limit int) ([]*Instance, error) {
+	return c.listInstances(ctx, namespace, offset, limit)
+}
+
+func (c *Client) listInstances(ctx context.Context, namespace string, offset, limit int) ([]*Instance, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.GetWorkflowInstancesRequest{
+		Namespace: &namespace,
+	}
+	if offset > 0 {
+		o := int32(offset)
+		request.Offset = &o
+	}
+	if limit > 0 {
+		l := int32(limit)
+		request.Limit = &l
+	}
+
+	// send grpc request
+	resp, err := c.ingress.GetWorkflowInstances(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	list := make([]*Instance, len(resp.GetWorkflowInstances()))
+	for i, in := range resp.GetWorkflowInstances() {
+		list[i] = &Instance{
+			ID:        in.GetId(),
+			Status:    in.GetStatus(),
+			BeginTime: timeOf(in.GetBeginTime()),
+		}
+	}
+
+	return list, nil
+}
+
+// GetInstance returns a workflow instance.
+func (c *Client) GetInstance(ctx context.Context, id string) (*Instance, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.GetWorkflowInstanceRequest{
+		Id: &id,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.GetWorkflowInstance(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	in := &Instance{
+		ID:           resp.GetId(),
+		Status:       resp.GetStatus(),
+		InvokedBy:    resp.GetInvokedBy(),
+		Revision:     resp.GetRevision(),
+		BeginTime:    timeOf(resp.GetBeginTime()),
+		Flow:         resp.GetFlow(),
+		Input:        resp.GetInput(),
+		Output:       resp.GetOutput(),
+		ErrorCode:    resp.GetErrorCode(),
+		ErrorMessage: resp.GetErrorMessage(),
+	}
+
+	if resp.GetEndTime() != nil {
+		t := timeOf(resp.GetEndTime())
+		in.EndTime = &t
+	}
+
+	return in, nil
+}
+
+// CancelInstance stops a running workflow instance.
+func (c *Client) CancelInstance(ctx context.Context, id string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.CancelWorkflowInstanceRequest{
+		Id: &id,
+	}
+
+	// send grpc request
+	_, err := c.ingress.CancelWorkflowInstance(ctx, &request)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+// WaitInstance polls the instance every interval until it has finished or
+// ctx is cancelled, returning its final state.
+func (c *Client) WaitInstance(ctx context.Context, id string, interval time.Duration) (*Instance, error) {
+	for {
+		in, err := c.GetInstance(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if in.Finished() {
+			return in, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// LogOptions selects which log entries of an instance are returned.
+type LogOptions struct {
+	// Offset is the index of the first entry.
+	Offset int
+	// Limit is the maximum number of entries, zero returns every entry
+	// after Offset.
+	Limit int
+	// Tail, if set, returns only the last Tail of the selected entries.
+	Tail int
+}
+
+// logsPageSize is the number of entries requested at a time.
+const logsPageSize = 1000
+
+// InstanceLogs returns the logs of a workflow instance, requesting them one
+// page at a time until an empty page is returned or the range selected by
+// opts is exhausted. A nil opts returns every entry.
+func (c *Client) InstanceLogs(ctx context.Context, id string, opts *LogOptions) ([]*LogEntry, error) {
+	logs, _, err := c.collectLogs(ctx, id, opts)
+	return logs, err
+}
+
+// collectLogs returns the entries selected by opts and the offset following
+// the last entry read.
+func (c *Client) collectLogs(ctx context.Context, id string, opts *LogOptions) ([]*LogEntry, int, error) {
+	if opts == nil {
+		opts = new(LogOptions)
+	}
+
+	var logs []*LogEntry
+	offset := opts.Offset
+	read := 0
+
+	for {
+		size := logsPageSize
+		if opts.Limit > 0 && opts.Limit-read < size {
+			size = opts.Limit - read
+		}
+
+		page, err := c.logsPage(ctx, id, offset, size)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		logs = append(logs, page...)
+		if opts.Tail > 0 && len(logs) > opts.Tail {
+			logs = logs[len(logs)-opts.Tail:]
+		}
+
+		offset += len(page)
+		read += len(page)
+
+		// the server may return fewer entries than requested even if there
+		// are more, so only an empty page marks the end
+		if len(page) == 0 || (opts.Limit > 0 && read >= opts.Limit) {
+			return logs, offset, nil
+		}
+	}
+}
+
+// FollowLogs calls fn for every log entry of the instance as it is written,
+// polling for new entries every interval until the instance has finished or
+// ctx is cancelled. Offset and Tail of opts select the entries shown first,
+// Limit is ignored.
+func (c *Client) FollowLogs(ctx context.Context, id string, opts *LogOptions, interval time.Duration, fn func(*LogEntry) error) error {
+	first := LogOptions{}
+	if opts != nil {
+		first.Offset = opts.Offset
+		first.Tail = opts.Tail
+	}
+
+	// check the status first so that logs written while finishing are
+	// fetched below
+	in, err := c.GetInstance(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	logs, offset, err := c.collectLogs(ctx, id, &first)
+	if err != nil {
+		return err
+	}
+
+	for {
+		for _, l := range logs {
+			err = fn(l)
+			if err != nil {
+				return err
+			}
+		}
+
+		if in.Finished() {
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		in, err = c.GetInstance(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		logs, offset, err = c.collectLogs(ctx, id, &LogOptions{Offset: offset})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// logsPage returns up to limit log entries starting at offset.
+func (c *Client) logsPage(ctx context.Context, id string, offset, limit int) ([]*LogEntry, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	o := int32(offset)
+	l := int32(limit)
+
+	// prepare request
+	request := ingress.GetWorkflowInstanceLogsRequest{
+		InstanceId: &id,
+		Offset:     &o,
+		Limit:      &l,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.GetWorkflowInstanceLogs(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	logs := make([]*LogEntry, len(resp.GetWorkflowInstanceLogs()))
+	for i, l := range resp.GetWorkflowInstanceLogs() {
+		logs[i] = &LogEntry{
+			Timestamp: timeOf(l.GetTimestamp()),
+			Message:   l.GetMessage(),
+			Context:   l.GetContext(),
+		}
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vorteil/direktiv/pkg/ingress"
+	"google.golang.org/grpc"
+)
+
+// fakeLogs serves the log entries of a single instance, returning at most
+// pageSize entries per request like a server that caps its pages.
+type fakeLogs struct {
+	ingress.DirektivIngressClient
+
+	entries  []string
+	pageSize int
+
+	// statuses are returned by successive GetWorkflowInstance calls, and
+	// appends are added to the entries before the call of the same index
+	// returns
+	statuses []string
+	appends  [][]string
+	calls    int
+}
+
+func (f *fakeLogs) GetWorkflowInstanceLogs(ctx context.Context, in *ingress.GetWorkflowInstanceLogsRequest, opts ...grpc.CallOption) (*ingress.GetWorkflowInstanceLogsResponse, error) {
+	start := int(in.GetOffset())
+	n := int(in.GetLimit())
+	if f.pageSize > 0 && n > f.pageSize {
+		n = f.pageSize
+	}
+
+	resp := new(ingress.GetWorkflowInstanceLogsResponse)
+	for i := start; i < start+n && i < len(f.entries); i++ {
+		msg := f.entries[i]
+		resp.WorkflowInstanceLogs = append(resp.WorkflowInstanceLogs, &ingress.GetWorkflowInstanceLogsResponse_WorkflowInstanceLog{
+			Message: &msg,
+		})
+	}
+
+	return resp, nil
+}
+
+func (f *fakeLogs) GetWorkflowInstance(ctx context.Context, in *ingress.GetWorkflowInstanceRequest, opts ...grpc.CallOption) (*ingress.GetWorkflowInstanceResponse, error) {
+	if f.calls >= len(f.statuses) {
+		return nil, fmt.Errorf("unexpected call %d", f.calls)
+	}
+
+	if f.calls < len(f.appends) {
+		f.entries = append(f.entries, f.appends[f.calls]...)
+	}
+	status := f.statuses[f.calls]
+	f.calls++
+
+	return &ingress.GetWorkflowInstanceResponse{
+		Id:     in.Id,
+		Status: &status,
+	}, nil
+}
+
+func entries(from, to int) []string {
+	var out []string
+	for i := from; i < to; i++ {
+		out = append(out, fmt.Sprintf("%d", i))
+	}
+	return out
+}
+
+func messages(logs []*LogEntry) []string {
+	var out []string
+	for _, l := range logs {
+		out = append(out, l.Message)
+	}
+	return out
+}
+
+func TestInstanceLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageSize int
+		opts     *LogOptions
+		want     []string
+	}{
+		{"all", 0, nil, entries(0, 10)},
+		{"short pages", 3, nil, entries(0, 10)},
+		{"offset", 3, &LogOptions{Offset: 4}, entries(4, 10)},
+		{"offset past the end", 3, &LogOptions{Offset: 12}, nil},
+		{"limit within a page", 0, &LogOptions{Limit: 4}, entries(0, 4)},
+		{"limit across short pages", 3, &LogOptions{Limit: 5}, entries(0, 5)},
+		{"limit on a page boundary", 3, &LogOptions{Limit: 6}, entries(0, 6)},
+		{"limit past the end", 3, &LogOptions{Limit: 20}, entries(0, 10)},
+		{"offset and limit", 3, &LogOptions{Offset: 2, Limit: 5}, entries(2, 7)},
+		{"tail", 3, &LogOptions{Tail: 4}, entries(6, 10)},
+		{"tail longer than the logs", 3, &LogOptions{Tail: 20}, entries(0, 10)},
+		{"tail of limit", 3, &LogOptions{Limit: 5, Tail: 2}, entries(3, 5)},
+		{"tail of offset and limit", 3, &LogOptions{Offset: 1, Limit: 7, Tail: 3}, entries(5, 8)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{ingress: &fakeLogs{entries: entries(0, 10), pageSize: tt.pageSize}}
+
+			logs, err := c.InstanceLogs(context.Background(), "ns/wf/1", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := messages(logs); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowLogs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *LogOptions
+		want []string
+	}{
+		{"from the start", nil, entries(0, 9)},
+		{"offset", &LogOptions{Offset: 3}, entries(3, 9)},
+		{"tail", &LogOptions{Tail: 2}, entries(2, 9)},
+		{"limit is ignored", &LogOptions{Limit: 1}, entries(0, 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeLogs{
+				pageSize: 2,
+				statuses: []string{StatusPending, StatusPending, StatusPending, StatusComplete},
+				appends:  [][]string{entries(0, 4), entries(4, 7), nil, entries(7, 9)},
+			}
+			c := &Client{ingress: f}
+
+			var got []string
+			err := c.FollowLogs(context.Background(), "ns/wf/1", tt.opts, time.Millisecond, func(l *LogEntry) error {
+				got = append(got, l.Message)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if f.calls != len(f.statuses) {
+				t.Fatalf("stopped after %d status checks, want %d", f.calls, len(f.statuses))
+			}
+		})
+	}
+}
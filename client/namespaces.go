@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+
+	"github.com/vorteil/direktiv/pkg/ingress"
+)
+
+// Namespace is a direktiv namespace.
+type Namespace struct {
+	Name string `json:"name"`
+}
+
+// ListNamespaces returns all namespaces.
+func (c *Client) ListNamespaces(ctx context.Context) ([]*Namespace, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send grpc request
+	resp, err := c.ingress.GetNamespaces(ctx, &ingress.GetNamespacesRequest{})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	list := make([]*Namespace, len(resp.GetNamespaces()))
+	for i, ns := range resp.GetNamespaces() {
+		list[i] = &Namespace{
+			Name: ns.GetName(),
+		}
+	}
+
+	return list, nil
+}
+
+// CreateNamespace creates a new namespace.
+func (c *Client) CreateNamespace(ctx context.Context, name string) (*Namespace, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.AddNamespaceRequest{
+		Name: &name,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.AddNamespace(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &Namespace{Name: resp.GetName()}, nil
+}
+
+// DeleteNamespace deletes a namespace and everything in it.
+func (c *Client) DeleteNamespace(ctx context.Context, name string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.DeleteNamespaceRequest{
+		Name: &name,
+	}
+
+	// send grpc request
+	_, err := c.ingress.DeleteNamespace(ctx, &request)
+	return wrapError(err)
+}
+
+// SendEvent broadcasts a cloud event to the namespace.
+func (c *Client) SendEvent(ctx context.Context, namespace string, cloudevent []byte) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.BroadcastEventRequest{
+		Namespace:  &namespace,
+		Cloudevent: cloudevent,
+	}
+
+	// send grpc request
+	_, err := c.ingress.BroadcastEvent(ctx, &request)
+	return wrapError(err)
+}
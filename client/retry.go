@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"path"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy controls how idempotent calls that fail with a transient error
+// are retried. No attempt is made past the deadline of the call.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first, a
+	// value of 1 or less disables retries.
+	MaxAttempts int
+	// Timeout is the deadline of each attempt within the deadline of the
+	// call, so that an attempt running out of time can be retried with
+	// DeadlineExceeded. Zero gives every attempt the deadline of the call.
+	Timeout time.Duration
+	// InitialBackoff is the wait before the first retry, which is multiplied
+	// by Multiplier for each further retry up to MaxBackoff. Every wait is
+	// randomised by up to half its length.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Codes are the status codes that are retried.
+	Codes []codes.Code
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Millisecond * 100,
+		MaxBackoff:     time.Second * 2,
+		Multiplier:     2,
+		Codes:          []codes.Code{codes.Unavailable, codes.DeadlineExceeded},
+	}
+}
+
+// jitter randomises the backoff. It is seeded here rather than using the
+// global source, which is not seeded for this module's Go version, so that
+// clients started together do not retry in lockstep.
+var jitter = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// idempotentMethods are the ingress methods that are safe to retry.
+var idempotentMethods = map[string]bool{
+	"GetNamespaces":           true,
+	"GetWorkflows":            true,
+	"GetWorkflowById":         true,
+	"GetWorkflowInstance":     true,
+	"GetWorkflowInstances":    true,
+	"GetWorkflowInstanceLogs": true,
+	"GetSecrets":              true,
+	"GetRegistries":           true,
+}
+
+// UnaryInterceptor returns a client interceptor that applies the policy to
+// idempotent ingress methods.
+func (p *RetryPolicy) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !idempotentMethods[path.Base(method)] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		backoff := p.InitialBackoff
+
+		for attempt := 1; ; attempt++ {
+			actx, cancel := ctx, context.CancelFunc(func() {})
+			if p.Timeout > 0 {
+				actx, cancel = context.WithTimeout(ctx, p.Timeout)
+			}
+			err := invoker(actx, method, req, reply, cc, opts...)
+			cancel()
+			if err == nil || attempt >= p.MaxAttempts || !p.retryable(err) || ctx.Err() != nil {
+				return err
+			}
+
+			// wait half the backoff plus up to half again at random
+			wait := backoff / 2
+			if wait > 0 {
+				jitter.Lock()
+				wait += time.Duration(jitter.Int63n(int64(wait)))
+				jitter.Unlock()
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+
+			backoff = time.Duration(float64(backoff) * p.Multiplier)
+			if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+				backoff = p.MaxBackoff
+			}
+		}
+	}
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	code := status.Code(err)
+	for _, c := range p.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
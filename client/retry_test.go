@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryInterceptor(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond * 4,
+		Multiplier:     2,
+		Codes:          []codes.Code{codes.Unavailable},
+	}
+
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	tests := []struct {
+		name     string
+		method   string
+		errs     []error
+		attempts int
+		code     codes.Code
+	}{
+		{"success", "/ingress.DirektivIngress/GetWorkflows", nil, 1, codes.OK},
+		{"retried until success", "/ingress.DirektivIngress/GetWorkflows", []error{unavailable, unavailable}, 3, codes.OK},
+		{"retried until max attempts", "/ingress.DirektivIngress/GetWorkflows", []error{unavailable, unavailable, unavailable, unavailable, unavailable}, 4, codes.Unavailable},
+		{"error not retried", "/ingress.DirektivIngress/GetWorkflows", []error{status.Error(codes.NotFound, "not found")}, 1, codes.NotFound},
+		{"method not idempotent", "/ingress.DirektivIngress/AddWorkflow", []error{unavailable, unavailable}, 1, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			}
+
+			err := policy.UnaryInterceptor()(context.Background(), tt.method, nil, nil, nil, invoker)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("got code %v, want %v", code, tt.code)
+			}
+			if attempts != tt.attempts {
+				t.Fatalf("got %d attempts, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryInterceptorStopsAtDeadline(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	attempts := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempts++
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+
+	start := time.Now()
+	err := policy.UnaryInterceptor()(ctx, "/ingress.DirektivIngress/GetWorkflows", nil, nil, nil, invoker)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want the last error", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("got %d attempts, want 1", attempts)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("retry did not stop at the deadline")
+	}
+}
+
+func TestRetryInterceptorAttemptTimeout(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.Timeout = time.Millisecond * 10
+
+	attempts := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempts++
+		if attempts < 3 {
+			<-ctx.Done()
+			return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+		}
+		return nil
+	}
+
+	err := policy.UnaryInterceptor()(context.Background(), "/ingress.DirektivIngress/GetWorkflows", nil, nil, nil, invoker)
+	if err != nil {
+		t.Fatalf("got %v, want success after the timed out attempts", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("got %d attempts, want 3", attempts)
+	}
+}
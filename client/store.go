@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+
+	"github.com/vorteil/direktiv/pkg/ingress"
+)
+
+// Secret is a named secret stored in a namespace. Its value cannot be read
+// back.
+type Secret struct {
+	Name string `json:"name"`
+}
+
+// Registry is a container registry that a namespace can pull images from.
+type Registry struct {
+	Name string `json:"name"`
+}
+
+// ListSecrets returns the secrets stored in a namespace.
+func (c *Client) ListSecrets(ctx context.Context, namespace string) ([]*Secret, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.GetSecretsRequest{
+		Namespace: &namespace,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.GetSecrets(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	list := make([]*Secret, len(resp.GetSecrets()))
+	for i, s := range resp.GetSecrets() {
+		list[i] = &Secret{
+			Name: s.GetName(),
+		}
+	}
+
+	return list, nil
+}
+
+// CreateSecret stores a secret in a namespace.
+func (c *Client) CreateSecret(ctx context.Context, namespace, name string, value []byte) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.StoreSecretRequest{
+		Namespace: &namespace,
+		Name:      &name,
+		Data:      value,
+	}
+
+	// send grpc request
+	_, err := c.ingress.StoreSecret(ctx, &request)
+	return wrapError(err)
+}
+
+// DeleteSecret removes a secret from a namespace.
+func (c *Client) DeleteSecret(ctx context.Context, namespace, name string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.DeleteSecretRequest{
+		Namespace: &namespace,
+		Name:      &name,
+	}
+
+	// send grpc request
+	_, err := c.ingress.DeleteSecret(ctx, &request)
+	return wrapError(err)
+}
+
+// ListRegistries returns the registries configured for a namespace.
+func (c *Client) ListRegistries(ctx context.Context, namespace string) ([]*Registry, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.GetRegistriesRequest{
+		Namespace: &namespace,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.GetRegistries(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	list := make([]*Registry, len(resp.GetRegistries()))
+	for i, r := range resp.GetRegistries() {
+		list[i] = &Registry{
+			Name: r.GetName(),
+		}
+	}
+
+	return list, nil
+}
+
+// CreateRegistry adds a registry to a namespace. Auth is the 'user!token'
+// string direktiv uses to authenticate with the registry.
+func (c *Client) CreateRegistry(ctx context.Context, namespace, url, auth string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.StoreRegistryRequest{
+		Namespace: &namespace,
+		Name:      &url,
+		Data:      []byte(auth),
+	}
+
+	// send grpc request
+	_, err := c.ingress.StoreRegistry(ctx, &request)
+	return wrapError(err)
+}
+
+// DeleteRegistry removes a registry from a namespace.
+func (c *Client) DeleteRegistry(ctx context.Context, namespace, url string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.DeleteRegistryRequest{
+		Namespace: &namespace,
+		Name:      &url,
+	}
+
+	// send grpc request
+	_, err := c.ingress.DeleteRegistry(ctx, &request)
+	return wrapError(err)
+}
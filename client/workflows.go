@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/vorteil/direktiv/pkg/ingress"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Workflow is a workflow stored in a namespace.
+type Workflow struct {
+	UID         string    `json:"uid"`
+	ID          string    `json:"id"`
+	Revision    int32     `json:"revision"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Description string    `json:"description,omitempty"`
+	// Definition is the YAML source of the workflow. It is only populated
+	// by GetWorkflow.
+	Definition []byte `json:"-"`
+}
+
+// ListWorkflows returns the workflows in a namespace.
+func (c *Client) ListWorkflows(ctx context.Context, namespace string) ([]*Workflow, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.GetWorkflowsRequest{
+		Namespace: &namespace,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.GetWorkflows(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	list := make([]*Workflow, len(resp.GetWorkflows()))
+	for i, wf := range resp.GetWorkflows() {
+		list[i] = &Workflow{
+			UID:         wf.GetUid(),
+			ID:          wf.GetId(),
+			Revision:    wf.GetRevision(),
+			Active:      wf.GetActive(),
+			CreatedAt:   timeOf(wf.GetCreatedAt()),
+			Description: wf.GetDescription(),
+		}
+	}
+
+	return list, nil
+}
+
+// GetWorkflow returns a workflow including its definition.
+func (c *Client) GetWorkflow(ctx context.Context, namespace, id string) (*Workflow, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.GetWorkflowByIdRequest{
+		Namespace: &namespace,
+		Id:        &id,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.GetWorkflowById(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &Workflow{
+		UID:         resp.GetUid(),
+		ID:          resp.GetId(),
+		Revision:    resp.GetRevision(),
+		Active:      resp.GetActive(),
+		CreatedAt:   timeOf(resp.GetCreatedAt()),
+		Description: resp.GetDescription(),
+		Definition:  resp.GetWorkflow(),
+	}, nil
+}
+
+// CreateWorkflow adds a workflow to a namespace. The workflow ID is read
+// from the definition.
+func (c *Client) CreateWorkflow(ctx context.Context, namespace string, definition []byte) (*Workflow, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.AddWorkflowRequest{
+		Namespace: &namespace,
+		Workflow:  definition,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.AddWorkflow(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &Workflow{
+		UID:        resp.GetUid(),
+		ID:         resp.GetId(),
+		Revision:   resp.GetRevision(),
+		Active:     resp.GetActive(),
+		CreatedAt:  timeOf(resp.GetCreatedAt()),
+		Definition: definition,
+	}, nil
+}
+
+// UpdateWorkflow replaces the definition of an existing workflow.
+func (c *Client) UpdateWorkflow(ctx context.Context, namespace, id string, definition []byte) (*Workflow, error) {
+	wf, err := c.GetWorkflow(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.updateWorkflow(ctx, wf.UID, definition, nil)
+}
+
+// ToggleWorkflow enables a disabled workflow or disables an enabled one,
+// returning the workflow with its new state.
+func (c *Client) ToggleWorkflow(ctx context.Context, namespace, id string) (*Workflow, error) {
+	wf, err := c.GetWorkflow(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+
+	active := !wf.Active
+	return c.updateWorkflow(ctx, wf.UID, wf.Definition, &active)
+}
+
+func (c *Client) updateWorkflow(ctx context.Context, uid string, definition []byte, active *bool) (*Workflow, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.UpdateWorkflowRequest{
+		Uid:      &uid,
+		Workflow: definition,
+		Active:   active,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.UpdateWorkflow(ctx, &request)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &Workflow{
+		UID:        resp.GetUid(),
+		ID:         resp.GetId(),
+		Revision:   resp.GetRevision(),
+		Active:     resp.GetActive(),
+		CreatedAt:  timeOf(resp.GetCreatedAt()),
+		Definition: definition,
+	}, nil
+}
+
+// DeleteWorkflow removes a workflow from a namespace.
+func (c *Client) DeleteWorkflow(ctx context.Context, namespace, id string) error {
+	wf, err := c.GetWorkflow(ctx, namespace, id)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.DeleteWorkflowRequest{
+		Uid: &wf.UID,
+	}
+
+	// send grpc request
+	_, err = c.ingress.DeleteWorkflow(ctx, &request)
+	return wrapError(err)
+}
+
+// InvokeWorkflow starts a new instance of a workflow with the given JSON
+// input, returning the ID of the instance.
+func (c *Client) InvokeWorkflow(ctx context.Context, namespace, id string, input []byte) (string, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// prepare request
+	request := ingress.InvokeWorkflowRequest{
+		Namespace:  &namespace,
+		Input:      input,
+		WorkflowId: &id,
+	}
+
+	// send grpc request
+	resp, err := c.ingress.InvokeWorkflow(ctx, &request)
+	if err != nil {
+		return "", wrapError(err)
+	}
+
+	return resp.GetInstanceId(), nil
+}
+
+// timeOf converts a protobuf timestamp, returning the zero time for nil.
+func timeOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+	"github.com/vorteil/direkcli/client"
+	"github.com/vorteil/direkcli/pkg/output"
+	"gopkg.in/yaml.v3"
+)
+
+var flagApplyNamespace string
+var flagApplyFiles []string
+var flagPrune bool
+var flagDryRun bool
+
+// Results of applying a workflow file.
+const (
+	applyCreated   = "created"
+	applyUpdated   = "updated"
+	applyUnchanged = "unchanged"
+	applyPruned    = "pruned"
+	applyFailed    = "failed"
+)
+
+// workflowFile is a workflow definition read from a local file.
+type workflowFile struct {
+	Path       string
+	ID         string
+	Definition []byte
+}
+
+// warnSkipped warns about each file skipped by readWorkflowFiles.
+func warnSkipped(skipped []string) {
+	for _, path := range skipped {
+		logger.Warnf("%s: not a workflow, it has no id, skipping", path)
+	}
+}
+
+// applyResult records what apply did with a single workflow.
+type applyResult struct {
+	ID       string `json:"id"`
+	File     string `json:"file,omitempty"`
+	Result   string `json:"result"`
+	Revision int32  `json:"revision,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// readWorkflowFiles reads the workflow definitions in each path, which may
+// be a file or a directory searched recursively for .yaml and .yml files.
+// Hidden files and directories are not searched, and files without an id
+// found in a directory are not workflows and are returned as skipped. A file
+// named directly must have an id, and ids must be unique.
+func readWorkflowFiles(paths []string) ([]*workflowFile, []string, error) {
+	var files []*workflowFile
+	var skipped []string
+	seen := make(map[string]string)
+
+	add := func(path string, found bool) error {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var def struct {
+			ID string `yaml:"id"`
+		}
+		err = yaml.Unmarshal(b, &def)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		if def.ID == "" {
+			if found {
+				skipped = append(skipped, path)
+				return nil
+			}
+			return fmt.Errorf("%s: workflow has no id", path)
+		}
+
+		if prev, ok := seen[def.ID]; ok {
+			return fmt.Errorf("%s: workflow '%s' is also defined in %s", path, def.ID, prev)
+		}
+		seen[def.ID] = path
+
+		files = append(files, &workflowFile{
+			Path:       path,
+			ID:         def.ID,
+			Definition: b,
+		})
+		return nil
+	}
+
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !fi.IsDir() {
+			err = add(p, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		err = filepath.Walk(p, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			// skip things like .git and .github/workflows, but not the
+			// directory given, which may well be "."
+			if path != p && strings.HasPrefix(fi.Name(), ".") {
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if fi.IsDir() {
+				return nil
+			}
+
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml":
+				return add(path, true)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ID < files[j].ID
+	})
+
+	return files, skipped, nil
+}
+
+// sameDefinition reports whether two definitions differ only in trailing
+// whitespace.
+func sameDefinition(a, b []byte) bool {
+	return bytes.Equal(bytes.TrimRight(a, " \t\r\n"), bytes.TrimRight(b, " \t\r\n"))
+}
+
+// applyWorkflow creates the workflow in the file if it does not exist, or
+// updates it if its definition has changed. With dryRun set it only reports
+// what it would do.
+func applyWorkflow(ctx context.Context, namespace string, f *workflowFile, dryRun bool) (*applyResult, error) {
+	r := &applyResult{
+		ID:   f.ID,
+		File: f.Path,
+	}
+
+	wf, err := api.GetWorkflow(ctx, namespace, f.ID)
+	if errors.Is(err, client.ErrNotFound) {
+		r.Result = applyCreated
+		if dryRun {
+			return r, nil
+		}
+
+		wf, err = api.CreateWorkflow(ctx, namespace, f.Definition)
+		if err != nil {
+			return nil, err
+		}
+		r.Revision = wf.Revision
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.Revision = wf.Revision
+	if sameDefinition(wf.Definition, f.Definition) {
+		r.Result = applyUnchanged
+		return r, nil
+	}
+
+	r.Result = applyUpdated
+	if dryRun {
+		return r, nil
+	}
+
+	wf, err = api.UpdateWorkflow(ctx, namespace, f.ID, f.Definition)
+	if err != nil {
+		return nil, err
+	}
+	r.Revision = wf.Revision
+	return r, nil
+}
+
+// pruneCandidates returns the IDs of the workflows in the namespace that
+// have no file.
+func pruneCandidates(ctx context.Context, namespace string, files []*workflowFile) ([]string, error) {
+	local := make(map[string]bool)
+	for _, f := range files {
+		local[f.ID] = true
+	}
+
+	list, err := api.ListWorkflows(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, wf := range list {
+		if !local[wf.ID] {
+			ids = append(ids, wf.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// applyCmd
+var applyCmd = generateCmd("apply -f FILE|DIR", "Creates or updates workflows to match local files", `Each workflow is looked up by the id in its file, created if it does not
+exist and updated if its definition differs, so that a directory of
+workflows, e.g. in a git repository, can be synced in one command:
+
+  direkcli apply -n ns -f workflows/
+
+Hidden files and directories are not searched, and YAML files without an
+id found in a directory are skipped with a warning.
+
+With --prune, workflows in the namespace that have no file are deleted
+after asking for confirmation, which requires --yes if stdin is not a
+terminal. If the deletion is declined the files are still applied, but
+apply exits with 1. Use --dry-run to see what would change
+without changing anything.`, func(cmd *cobra.Command, args []string) {
+	namespace := flagApplyNamespace
+	if namespace == "" {
+		namespace = withNamespace(nil, 1)[0]
+	}
+
+	if len(flagApplyFiles) == 0 {
+		logger.Errorf("no files provided, use -f FILE|DIR")
+		os.Exit(exitUsage)
+	}
+
+	files, skipped, err := readWorkflowFiles(flagApplyFiles)
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(exitUsage)
+	}
+	warnSkipped(skipped)
+
+	var prune []string
+	var declined bool
+	if flagPrune {
+		if len(files) == 0 {
+			logger.Errorf("refusing to prune without any workflow files")
+			os.Exit(exitUsage)
+		}
+
+		prune, err = pruneCandidates(cmd.Context(), namespace, files)
+		if err != nil {
+			fail(err)
+		}
+
+		if len(prune) > 0 && !flagDryRun && !flagYes {
+			if !stdinIsTerminal() {
+				logger.Errorf("refusing to prune without --yes as stdin is not a terminal")
+				os.Exit(exitUsage)
+			}
+
+			for _, id := range prune {
+				fmt.Fprintln(os.Stderr, id)
+			}
+			if !confirm(fmt.Sprintf("Delete %d workflows from '%s'?", len(prune), namespace)) {
+				logger.Printf("Not pruning, applying the workflow files only")
+				prune = nil
+				declined = true
+			}
+		}
+	}
+
+	var results []*applyResult
+	var last error
+
+	for _, f := range files {
+		r, err := applyWorkflow(cmd.Context(), namespace, f, flagDryRun)
+		if err != nil {
+			logger.Errorf("Unable to apply '%s': %v", f.Path, err)
+			last = err
+			r = &applyResult{
+				ID:     f.ID,
+				File:   f.Path,
+				Result: applyFailed,
+				Error:  err.Error(),
+			}
+		}
+		results = append(results, r)
+	}
+
+	for _, id := range prune {
+		r := &applyResult{
+			ID:     id,
+			Result: applyPruned,
+		}
+
+		if !flagDryRun {
+			err = api.DeleteWorkflow(cmd.Context(), namespace, id)
+			if err != nil {
+				logger.Errorf("Unable to delete workflow '%s': %v", id, err)
+				last = err
+				r.Result = applyFailed
+				r.Error = err.Error()
+			}
+		}
+		results = append(results, r)
+	}
+
+	printApplyResults(results)
+
+	if flagDryRun {
+		logger.Printf("Dry run, no changes were made")
+	}
+
+	if last != nil {
+		os.Exit(exitCode(last))
+	}
+	if declined {
+		os.Exit(exitError)
+	}
+}, cobra.NoArgs)
+
+func printApplyResults(results []*applyResult) {
+	items := make([]interface{}, len(results))
+	for i := range results {
+		items[i] = results[i]
+	}
+
+	printResult(&output.Result{
+		Object: &struct {
+			Workflows []*applyResult `json:"workflows"`
+		}{results},
+		Items: items,
+		Columns: []output.Column{
+			{Header: "ID", Value: func(item interface{}) string {
+				return item.(*applyResult).ID
+			}},
+			{Header: "File", Wide: true, Value: func(item interface{}) string {
+				return item.(*applyResult).File
+			}},
+			{Header: "Result", Value: func(item interface{}) string {
+				return item.(*applyResult).Result
+			}},
+			{Header: "Revision", Wide: true, Value: func(item interface{}) string {
+				if rev := item.(*applyResult).Revision; rev > 0 {
+					return fmt.Sprintf("%d", rev)
+				}
+				return ""
+			}},
+		},
+		Name: func(item interface{}) string {
+			return item.(*applyResult).ID
+		},
+	}, "No workflows found")
+}
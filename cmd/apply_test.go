@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTree writes files, keyed by slash separated path, under a temporary
+// directory and returns it.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadWorkflowFiles(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.yaml":                   "id: a\n",
+		"sub/b.YML":                "id: b\n",
+		"sub/notes.txt":            "id: notes\n",
+		"sub/values.yaml":          "replicas: 2\n",
+		".github/workflows/ci.yml": "on: push\n",
+		".hidden.yaml":             "id: hidden\n",
+		"dup/a.yaml":               "id: a\n",
+		"broken/broken.yaml":       "id: [\n",
+	})
+	path := func(name string) string {
+		return filepath.Join(dir, filepath.FromSlash(name))
+	}
+
+	tests := []struct {
+		name    string
+		paths   []string
+		ids     []string
+		skipped []string
+		err     bool
+	}{
+		{name: "file", paths: []string{path("a.yaml")}, ids: []string{"a"}},
+		{name: "directory", paths: []string{path("sub")}, ids: []string{"b"}, skipped: []string{path("sub/values.yaml")}},
+		{name: "files and directories", paths: []string{path("sub"), path("a.yaml")}, ids: []string{"a", "b"}, skipped: []string{path("sub/values.yaml")}},
+		{name: "hidden directory named", paths: []string{path(".github")}, skipped: []string{path(".github/workflows/ci.yml")}},
+		{name: "file without id", paths: []string{path("sub/values.yaml")}, err: true},
+		{name: "duplicate id", paths: []string{path("a.yaml"), path("dup")}, err: true},
+		{name: "invalid yaml in directory", paths: []string{path("broken")}, err: true},
+		{name: "missing", paths: []string{path("missing")}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, skipped, err := readWorkflowFiles(tt.paths)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %d files", len(files))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, f := range files {
+				ids = append(ids, f.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("got ids %v, want %v", ids, tt.ids)
+			}
+			if !reflect.DeepEqual(skipped, tt.skipped) {
+				t.Errorf("got skipped %v, want %v", skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestReadWorkflowFilesSkipsHidden(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"workflows/a.yaml":         "id: a\n",
+		".github/workflows/ci.yml": "on: push\n",
+		".git/config.yaml":         "id: git\n",
+		".hidden.yaml":             "id: hidden\n",
+	})
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	files, skipped, err := readWorkflowFiles([]string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].ID != "a" {
+		t.Fatalf("got %d files, want only workflow a", len(files))
+	}
+	if len(skipped) != 0 {
+		t.Fatalf("got skipped %v, want none", skipped)
+	}
+}
+
+func TestSameDefinition(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "equal", a: "id: a\n", b: "id: a\n", want: true},
+		{name: "trailing newline", a: "id: a", b: "id: a\n\n", want: true},
+		{name: "trailing whitespace", a: "id: a \t\r\n", b: "id: a", want: true},
+		{name: "empty", want: true},
+		{name: "leading whitespace", a: "\nid: a", b: "id: a"},
+		{name: "different", a: "id: a\n", b: "id: b\n"},
+		{name: "inner whitespace", a: "id:  a\n", b: "id: a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sameDefinition([]byte(tt.a), []byte(tt.b))
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+	"github.com/vorteil/direkcli/pkg/config"
+	log "github.com/vorteil/direkcli/pkg/log"
+	"gopkg.in/yaml.v3"
+)
+
+// configPreRunE replaces the root PersistentPreRunE for commands that only
+// work with the config file and do not need a connection.
+func configPreRunE(cmd *cobra.Command, args []string) error {
+	logger = log.GetLogger()
+
+	err := loadConfig()
+	if err != nil {
+		return setupFailed(cmd, err)
+	}
+	return nil
+}
+
+// configCmd
+var configCmd = &cobra.Command{
+	Use:               "config",
+	Short:             "View and modify connection profiles",
+	Long:              ``,
+	PersistentPreRunE: configPreRunE,
+}
+
+// configViewCmd
+var configViewCmd = generateCmd("view", "Print the contents of the config file, with tokens redacted", "", func(cmd *cobra.Command, args []string) {
+	if len(cfg.Profiles) == 0 {
+		logger.Printf("No profiles exist in '%s'", cfg.Path())
+		return
+	}
+
+	b, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		fail(err)
+	}
+	fmt.Print(string(b))
+}, cobra.ExactArgs(0))
+
+// configUseProfileCmd
+var configUseProfileCmd = generateCmd("use-profile PROFILE", "Sets the profile used by default", "", func(cmd *cobra.Command, args []string) {
+	err := cfg.UseProfile(args[0])
+	if err != nil {
+		fail(err)
+	}
+
+	err = cfg.Save()
+	if err != nil {
+		fail(err)
+	}
+	logger.Printf("Switched to profile '%s'", args[0])
+}, cobra.ExactArgs(1))
+
+// configSetCmd
+var configSetCmd = generateCmd("set KEY VALUE", "Sets a value on the profile selected with --profile, or the current profile", "Valid keys are: "+strings.Join(config.Keys, ", "), func(cmd *cobra.Command, args []string) {
+	name := cfg.ProfileName(flagProfile)
+
+	err := cfg.Profile(name).Set(args[0], args[1])
+	if err != nil {
+		fail(err)
+	}
+
+	err = cfg.Save()
+	if err != nil {
+		fail(err)
+	}
+	logger.Printf("Set '%s' on profile '%s'", args[0], name)
+}, cobra.ExactArgs(2))
+
+// loginCmd
+var loginCmd = &cobra.Command{
+	Use:               "login [TOKEN]",
+	Short:             "Stores a token on the profile selected with --profile, or the current profile",
+	Long:              "If TOKEN is omitted it is read from stdin. The profile must use TLS, as the token\nis never sent without it.",
+	Args:              cobra.RangeArgs(0, 1),
+	PersistentPreRunE: configPreRunE,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := cfg.ProfileName(flagProfile)
+		p := cfg.Profile(name)
+		if !p.UsesTLS() {
+			logger.Errorf("profile '%s' does not use TLS and a token is never sent without it, enable it with 'direkcli config set tls true' first", name)
+			os.Exit(exitUsage)
+		}
+
+		var token string
+		if len(args) == 1 {
+			token = args[0]
+		} else {
+			fmt.Fprint(os.Stderr, "Token: ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && err != io.EOF {
+				fail(err)
+			}
+			token = strings.TrimSpace(line)
+		}
+
+		if token == "" {
+			logger.Errorf("no token provided")
+			os.Exit(exitUsage)
+		}
+
+		p.Token = token
+		p.TokenFile = ""
+
+		err := cfg.Save()
+		if err != nil {
+			fail(err)
+		}
+		logger.Printf("Stored token on profile '%s'", name)
+	},
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cobra "github.com/spf13/cobra"
+	"github.com/vorteil/direkcli/client"
+	"github.com/vorteil/direkcli/pkg/output"
+)
+
+var flagDiffFiles []string
+
+// workflowDiffCmd
+var workflowDiffCmd = generateCmd("diff NAMESPACE [ID] -f FILE|DIR", "Shows how local workflow files differ from the server", `Every workflow file is compared with the workflow of the same id on the
+server, or only the workflow given by ID. Differences are shown as a
+unified diff from the server to the local file, and the command exits
+with code 8 if there are any, so it can be used to detect drift in CI:
+
+  direkcli workflows diff ns -f workflows/`, func(cmd *cobra.Command, args []string) {
+	namespace := args[0]
+
+	if len(flagDiffFiles) == 0 {
+		logger.Errorf("no files provided, use -f FILE|DIR")
+		os.Exit(exitUsage)
+	}
+
+	var files []*workflowFile
+	var err error
+
+	if len(args) == 2 {
+		files, err = selectWorkflowFile(flagDiffFiles, args[1])
+	} else {
+		var skipped []string
+		files, skipped, err = readWorkflowFiles(flagDiffFiles)
+		warnSkipped(skipped)
+	}
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(exitUsage)
+	}
+
+	differ := false
+	for _, f := range files {
+		var remote []byte
+		from := fmt.Sprintf("%s/%s", namespace, f.ID)
+
+		wf, err := api.GetWorkflow(cmd.Context(), namespace, f.ID)
+		if errors.Is(err, client.ErrNotFound) {
+			from += " (not found)"
+		} else if err != nil {
+			fail(err)
+		} else {
+			remote = wf.Definition
+		}
+
+		d, err := output.WriteDiff(os.Stdout, remote, f.Definition, from, f.Path)
+		if err != nil {
+			fail(err)
+		}
+		differ = differ || d
+	}
+
+	if differ {
+		os.Exit(exitDiffer)
+	}
+}, cobra.RangeArgs(1, 2))
+
+// selectWorkflowFile returns the file in paths defining the workflow id. A
+// single file is compared with the workflow id whatever id it defines, and
+// need not define one at all.
+func selectWorkflowFile(paths []string, id string) ([]*workflowFile, error) {
+	if len(paths) == 1 {
+		if fi, err := os.Stat(paths[0]); err == nil && !fi.IsDir() {
+			b, err := ioutil.ReadFile(paths[0])
+			if err != nil {
+				return nil, err
+			}
+			return []*workflowFile{{
+				Path:       paths[0],
+				ID:         id,
+				Definition: b,
+			}}, nil
+		}
+	}
+
+	files, _, err := readWorkflowFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.ID == id {
+			return []*workflowFile{f}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no file defines workflow '%s'", id)
+}
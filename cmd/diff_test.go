@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSelectWorkflowFile(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.yaml":          "id: a\n",
+		"other.yaml":      "id: other\n",
+		"noid.yaml":       "states: []\n",
+		"sub/b.yaml":      "id: b\n",
+		"sub/values.yaml": "replicas: 2\n",
+	})
+	path := func(name string) string {
+		return filepath.Join(dir, filepath.FromSlash(name))
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		id    string
+		want  string
+		err   bool
+	}{
+		{name: "single file", paths: []string{path("a.yaml")}, id: "a", want: path("a.yaml")},
+		{name: "single file with another id", paths: []string{path("other.yaml")}, id: "a", want: path("other.yaml")},
+		{name: "single file without id", paths: []string{path("noid.yaml")}, id: "a", want: path("noid.yaml")},
+		{name: "directory", paths: []string{path("sub")}, id: "b", want: path("sub/b.yaml")},
+		{name: "several files", paths: []string{path("a.yaml"), path("other.yaml")}, id: "other", want: path("other.yaml")},
+		{name: "not defined", paths: []string{path("sub")}, id: "a", err: true},
+		{name: "several files not defined", paths: []string{path("a.yaml"), path("other.yaml")}, id: "b", err: true},
+		{name: "missing file", paths: []string{path("missing.yaml")}, id: "a", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := selectWorkflowFile(tt.paths, tt.id)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %d files", len(files))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(files) != 1 {
+				t.Fatalf("got %d files, want 1", len(files))
+			}
+			if files[0].Path != tt.want || files[0].ID != tt.id {
+				t.Fatalf("got %s defining '%s', want %s defining '%s'", files[0].Path, files[0].ID, tt.want, tt.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	cobra "github.com/spf13/cobra"
+	"github.com/vorteil/direkcli/client"
+)
+
+// Exit codes, documented in the help of the root command.
+const (
+	exitError            = 1
+	exitUsage            = 2
+	exitNotFound         = 3
+	exitAlreadyExists    = 4
+	exitPermissionDenied = 5
+	exitUnavailable      = 6
+	exitInstanceFailed   = 7
+	exitDiffer           = 8
+)
+
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  unspecified error
+  2  invalid usage or invalid argument
+  3  resource not found
+  4  resource already exists
+  5  permission denied or not authenticated
+  6  server unavailable or request timed out
+  7  workflow instance did not complete successfully
+  8  local workflows differ from the server`
+
+// exitCode returns the exit code matching err.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, client.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, client.ErrAlreadyExists):
+		return exitAlreadyExists
+	case errors.Is(err, client.ErrPermissionDenied):
+		return exitPermissionDenied
+	case errors.Is(err, client.ErrUnavailable):
+		return exitUnavailable
+	case errors.Is(err, client.ErrInvalidArgument):
+		return exitUsage
+	}
+	return exitError
+}
+
+// fail logs err and exits with the matching exit code.
+func fail(err error) {
+	logger.Errorf("%v", err)
+	os.Exit(exitCode(err))
+}
+
+// setupError is an error that occurred while preparing to run a command,
+// e.g. reading the config file or connecting, rather than an error in its
+// usage.
+type setupError struct {
+	err error
+}
+
+func (e *setupError) Error() string {
+	return e.err.Error()
+}
+
+func (e *setupError) Unwrap() error {
+	return e.err
+}
+
+// setupFailed marks err as a setup error, which is not followed by the usage
+// of cmd and exits with the code matching err rather than exitUsage.
+func setupFailed(cmd *cobra.Command, err error) error {
+	cmd.SilenceUsage = true
+	return &setupError{err}
+}
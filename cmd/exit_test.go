@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vorteil/direkcli/client"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "not found", err: client.ErrNotFound, want: exitNotFound},
+		{name: "already exists", err: client.ErrAlreadyExists, want: exitAlreadyExists},
+		{name: "permission denied", err: client.ErrPermissionDenied, want: exitPermissionDenied},
+		{name: "unavailable", err: client.ErrUnavailable, want: exitUnavailable},
+		{name: "invalid argument", err: client.ErrInvalidArgument, want: exitUsage},
+		{name: "wrapped", err: fmt.Errorf("workflow 'x': %w", client.ErrNotFound), want: exitNotFound},
+		{name: "setup", err: &setupError{fmt.Errorf("connecting: %w", client.ErrUnavailable)}, want: exitUnavailable},
+		{name: "other", err: errors.New("something went wrong"), want: exitError},
+		{name: "setup other", err: &setupError{errors.New("bad config")}, want: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Fatalf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
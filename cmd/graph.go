@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+
+	cobra "github.com/spf13/cobra"
+	"github.com/vorteil/direkcli/pkg/definition"
+	log "github.com/vorteil/direkcli/pkg/log"
+)
+
+var flagGraphFile string
+var flagGraphFormat string
+
+// graphPreRunE checks the graph format before connecting, and skips the
+// connection entirely if the workflow is read from a local file.
+func graphPreRunE(cmd *cobra.Command, args []string) error {
+	err := definition.CheckGraphFormat(flagGraphFormat)
+	if err != nil {
+		return err
+	}
+
+	if flagGraphFile != "" {
+		logger = log.GetLogger()
+		return nil
+	}
+
+	return rootCmd.PersistentPreRunE(cmd, args)
+}
+
+// workflowGraphCmd
+var workflowGraphCmd = &cobra.Command{
+	Use:   "graph [NAMESPACE] ID | -f FILE",
+	Short: "Draws the state machine of a workflow",
+	Long: `The workflow is fetched from the server, or read from a local file with
+-f. It is drawn as an outline for the terminal by default, or as Graphviz
+DOT or a Mermaid flowchart with --format, e.g.
+
+  direkcli workflows graph ns my-workflow --format dot | dot -Tsvg > wf.svg`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if flagGraphFile != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return namespaceArgs(2)(cmd, args)
+	},
+	PersistentPreRunE: graphPreRunE,
+	Run: func(cmd *cobra.Command, args []string) {
+		var b []byte
+		var err error
+
+		if flagGraphFile != "" {
+			b, err = ioutil.ReadFile(flagGraphFile)
+			if err != nil {
+				fail(err)
+			}
+		} else {
+			args = withNamespace(args, 2)
+			wf, err := api.GetWorkflow(cmd.Context(), args[0], args[1])
+			if err != nil {
+				fail(err)
+			}
+			b = wf.Definition
+		}
+
+		wf, err := definition.Parse(b)
+		if err != nil {
+			fail(err)
+		}
+
+		err = definition.WriteGraph(os.Stdout, wf, flagGraphFormat)
+		if err != nil {
+			fail(err)
+		}
+	},
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var flagInputJSON string
+var flagSet []string
+var flagSetFile []string
+
+const inputHelp = `The input is read from the file given by --input ('-' for stdin) or taken
+from --input-json. Fields may be added to it, or to an empty object, with
+--set key=value and --set-file key=@path. Values of --set are always
+strings, while the contents of a --set-file are used as JSON if they are
+valid JSON and as a string otherwise, so that numbers, booleans and objects
+are set from a file, e.g.
+
+  direkcli workflows execute ns wf --set name=world --set version=1.10 --set-file config=@config.json`
+
+// readInput builds the workflow input from the input flags. It returns nil
+// if none of them were given, and an error if the result is not valid JSON.
+func readInput() ([]byte, error) {
+	if flagInputFile != "" && flagInputJSON != "" {
+		return nil, fmt.Errorf("--input and --input-json cannot be used together")
+	}
+
+	var b []byte
+	var err error
+
+	switch {
+	case flagInputFile == "-":
+		b, err = ioutil.ReadAll(os.Stdin)
+	case flagInputFile != "":
+		b, err = ioutil.ReadFile(flagInputFile)
+	case flagInputJSON != "":
+		b = []byte(flagInputJSON)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(flagSet) == 0 && len(flagSetFile) == 0 {
+		if len(b) > 0 && !json.Valid(b) {
+			return nil, fmt.Errorf("input is not valid JSON")
+		}
+		return b, nil
+	}
+
+	obj := make(map[string]interface{})
+	if len(strings.TrimSpace(string(b))) > 0 {
+		err = json.Unmarshal(b, &obj)
+		if err != nil {
+			return nil, fmt.Errorf("input must be a JSON object to be used with --set: %v", err)
+		}
+	}
+
+	for _, kv := range flagSet {
+		k, v, err := splitKeyValue(kv, "--set")
+		if err != nil {
+			return nil, err
+		}
+		obj[k] = v
+	}
+
+	for _, kv := range flagSetFile {
+		k, path, err := splitKeyValue(kv, "--set-file")
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := ioutil.ReadFile(strings.TrimPrefix(path, "@"))
+		if err != nil {
+			return nil, err
+		}
+		obj[k] = jsonOrString(data)
+	}
+
+	return json.Marshal(obj)
+}
+
+// splitKeyValue splits a 'key=value' argument of the named flag.
+func splitKeyValue(kv, flag string) (string, string, error) {
+	parts := strings.SplitN(kv, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("invalid %s '%s', expected key=value", flag, kv)
+	}
+	return parts[0], parts[1], nil
+}
+
+// jsonOrString returns the decoded value of b if it is valid JSON, or b as
+// a string otherwise.
+func jsonOrString(b []byte) interface{} {
+	var v interface{}
+	if json.Unmarshal(b, &v) == nil {
+		return v
+	}
+	return string(b)
+}
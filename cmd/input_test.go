@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadInput(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	object := write("object.json", `{"name": "file"}`)
+	array := write("array.json", `[1, 2]`)
+	invalid := write("invalid.json", `{"name":`)
+	text := write("text.txt", "hello\n")
+
+	tests := []struct {
+		name    string
+		file    string
+		json    string
+		stdin   string
+		set     []string
+		setFile []string
+		want    string
+		err     bool
+	}{
+		{name: "no input"},
+		{name: "file", file: object, want: `{"name": "file"}`},
+		{name: "stdin", file: "-", stdin: `{"name": "stdin"}`, want: `{"name": "stdin"}`},
+		{name: "json", json: `[1, 2]`, want: `[1, 2]`},
+		{name: "set without input", set: []string{"name=world", "count=3", "ok=true", "list=[1,2]", "version=1.10"}, want: `{"count":"3","list":"[1,2]","name":"world","ok":"true","version":"1.10"}`},
+		{name: "set overrides file", file: object, set: []string{"name=set", "extra=x"}, want: `{"extra":"x","name":"set"}`},
+		{name: "set overrides json", json: `{"name": "json"}`, set: []string{"name="}, want: `{"name":""}`},
+		{name: "set value with equals", set: []string{"q=a=b"}, want: `{"q":"a=b"}`},
+		{name: "set file", setFile: []string{"config=@" + object, "text=@" + text}, want: `{"config":{"name":"file"},"text":"hello\n"}`},
+		{name: "set file without at", setFile: []string{"config=" + object}, want: `{"config":{"name":"file"}}`},
+		{name: "file and json", file: object, json: `{}`, err: true},
+		{name: "invalid file", file: invalid, err: true},
+		{name: "invalid json", json: `{`, err: true},
+		{name: "missing file", file: filepath.Join(dir, "missing.json"), err: true},
+		{name: "set on array", file: array, set: []string{"a=b"}, err: true},
+		{name: "set without value", set: []string{"name"}, err: true},
+		{name: "set without key", set: []string{"=value"}, err: true},
+		{name: "set missing file", setFile: []string{"a=@" + filepath.Join(dir, "missing.json")}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagInputFile, flagInputJSON, flagSet, flagSetFile = tt.file, tt.json, tt.set, tt.setFile
+			defer func() {
+				flagInputFile, flagInputJSON, flagSet, flagSetFile = "", "", nil, nil
+			}()
+
+			if tt.file == "-" {
+				stdin := os.Stdin
+				defer func() { os.Stdin = stdin }()
+
+				os.Stdin, _ = os.Open(write("stdin", tt.stdin))
+				defer os.Stdin.Close()
+			}
+
+			b, err := readInput()
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tt.want {
+				t.Fatalf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vorteil/direkcli/client"
+	"github.com/vorteil/direkcli/pkg/output"
+)
+
+var flagListStatus []string
+var flagListWorkflow string
+var flagListSince string
+var flagListUntil string
+var flagListSort string
+var flagListOffset int
+var flagListLimit int
+var flagRaw bool
+
+const instanceListHelp = `Instances can be filtered by --status, --workflow and by their begin time
+with --since and --until, which take an RFC 3339 timestamp, a date such as
+2021-02-01, or a duration before now such as 24h. For example, the runs of
+workflow X that failed in the last day:
+
+  direkcli instances list ns --workflow X --status failed --since 24h
+
+The end time and duration of finished instances are only shown by the wide
+format, as each requires another request to the server.`
+
+// timeLayout is used to show times in tables.
+const timeLayout = "2006-01-02 15:04:05"
+
+// instanceLess compares two instances by one of the keys accepted by --sort.
+var instanceLess = map[string]func(a, b *client.Instance, now time.Time) bool{
+	"id": func(a, b *client.Instance, now time.Time) bool {
+		return a.ID < b.ID
+	},
+	"workflow": func(a, b *client.Instance, now time.Time) bool {
+		return a.Workflow() < b.Workflow()
+	},
+	"status": func(a, b *client.Instance, now time.Time) bool {
+		return a.Status < b.Status
+	},
+	"begin": func(a, b *client.Instance, now time.Time) bool {
+		return a.BeginTime.Before(b.BeginTime)
+	},
+	// instances that are still running sort after those that have ended
+	"end": func(a, b *client.Instance, now time.Time) bool {
+		if a.EndTime == nil || b.EndTime == nil {
+			return a.EndTime != nil && b.EndTime == nil
+		}
+		return a.EndTime.Before(*b.EndTime)
+	},
+	"duration": func(a, b *client.Instance, now time.Time) bool {
+		return instanceDuration(a, now) < instanceDuration(b, now)
+	},
+}
+
+// instanceSortKeys returns the keys accepted by --sort.
+func instanceSortKeys() []string {
+	keys := make([]string, 0, len(instanceLess))
+	for k := range instanceLess {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkInstanceSort checks the key given by --sort, which may be prefixed
+// with '-' to sort in descending order.
+func checkInstanceSort() error {
+	if flagListSort == "" {
+		return nil
+	}
+
+	key := strings.TrimPrefix(flagListSort, "-")
+	if _, ok := instanceLess[key]; !ok {
+		return fmt.Errorf("unknown sort key '%s', expected one of: %s", key, strings.Join(instanceSortKeys(), ", "))
+	}
+	return nil
+}
+
+// instanceFilter builds the filter selected by the instance list flags.
+func instanceFilter(now time.Time) (*client.InstanceFilter, error) {
+	f := &client.InstanceFilter{
+		Workflow: flagListWorkflow,
+	}
+
+	for _, s := range flagListStatus {
+		s = strings.ToLower(s)
+		valid := false
+		for _, status := range client.Statuses {
+			valid = valid || s == status
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown status '%s', expected one of: %s", s, strings.Join(client.Statuses, ", "))
+		}
+		f.Statuses = append(f.Statuses, s)
+	}
+
+	var err error
+
+	if flagListSince != "" {
+		f.Since, err = parseTime("since", flagListSince, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if flagListUntil != "" {
+		f.Until, err = parseTime("until", flagListUntil, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// parseTime parses the value of a time flag, given as an RFC 3339
+// timestamp, a date in the local time zone, or a duration before now.
+func parseTime(flag, value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --%s '%s', expected a timestamp, a date or a duration", flag, value)
+}
+
+// listInstances returns the instances in the namespace selected by the
+// instance list flags, which must have been checked already. Instances are
+// filtered and sorted before --offset and --limit apply, which are passed on
+// to the server if neither is needed.
+func listInstances(ctx context.Context, namespace string, f *client.InstanceFilter, now time.Time) ([]*client.Instance, error) {
+	key := strings.TrimPrefix(flagListSort, "-")
+
+	details := flagOutput == output.FormatWide
+
+	if f.IsZero() && key == "" {
+		list, err := api.ListInstancesPage(ctx, namespace, flagListOffset, flagListLimit)
+		if err == nil && details {
+			err = instanceDetails(ctx, list)
+		}
+		return list, err
+	}
+
+	all, err := api.ListInstances(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*client.Instance{}
+	for _, in := range all {
+		if f.Match(in) {
+			list = append(list, in)
+		}
+	}
+
+	if key == "end" || key == "duration" {
+		err = instanceDetails(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		details = false
+	}
+
+	list = pageInstances(list, now)
+
+	if details {
+		err = instanceDetails(ctx, list)
+	}
+
+	return list, err
+}
+
+// pageInstances sorts the instances by --sort and then applies --offset and
+// --limit. An offset past the end leaves an empty list, not nil, so that it
+// is printed as [] rather than null.
+func pageInstances(list []*client.Instance, now time.Time) []*client.Instance {
+	key := strings.TrimPrefix(flagListSort, "-")
+	desc := strings.HasPrefix(flagListSort, "-")
+
+	if less := instanceLess[key]; less != nil {
+		sort.SliceStable(list, func(i, j int) bool {
+			if desc {
+				return less(list[j], list[i], now)
+			}
+			return less(list[i], list[j], now)
+		})
+	}
+
+	offset := flagListOffset
+	if offset > len(list) {
+		offset = len(list)
+	}
+	if offset > 0 {
+		list = list[offset:]
+	}
+
+	if flagListLimit > 0 && flagListLimit < len(list) {
+		list = list[:flagListLimit]
+	}
+
+	return list
+}
+
+// instanceListResult lists the instances selected by the instance list
+// flags for printing.
+func instanceListResult(ctx context.Context, namespace string, f *client.InstanceFilter, now time.Time) (*output.Result, error) {
+	list, err := listInstances(ctx, namespace, f, now)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, len(list))
+	for i := range list {
+		items[i] = list[i]
+	}
+
+	return &output.Result{
+		Object: &struct {
+			WorkflowInstances []*client.Instance `json:"workflowInstances"`
+		}{list},
+		Items: items,
+		Columns: []output.Column{
+			{Header: "ID", Value: func(item interface{}) string {
+				return item.(*client.Instance).ID
+			}},
+			{Header: "Status", Value: func(item interface{}) string {
+				return item.(*client.Instance).Status
+			}},
+			{Header: "Begin", Value: func(item interface{}) string {
+				return formatTime(item.(*client.Instance).BeginTime)
+			}},
+			{Header: "End", Wide: true, Value: func(item interface{}) string {
+				if end := item.(*client.Instance).EndTime; end != nil {
+					return formatTime(*end)
+				}
+				return ""
+			}},
+			{Header: "Duration", Wide: true, Value: func(item interface{}) string {
+				return instanceDuration(item.(*client.Instance), now).Round(time.Millisecond).String()
+			}},
+		},
+		Name: func(item interface{}) string {
+			return item.(*client.Instance).ID
+		},
+		List: "workflowInstances",
+	}, nil
+}
+
+// endTimes caches the end times of finished instances, which do not change,
+// so that watching does not fetch every finished instance again on each
+// update.
+var endTimes = make(map[string]*time.Time)
+
+// instanceDetails fills in the end time of finished instances, which is not
+// returned when listing them.
+func instanceDetails(ctx context.Context, list []*client.Instance) error {
+	for i, in := range list {
+		if !in.Finished() {
+			continue
+		}
+
+		end, ok := endTimes[in.ID]
+		if !ok {
+			x, err := api.GetInstance(ctx, in.ID)
+			if err != nil {
+				return err
+			}
+			end = x.EndTime
+			endTimes[in.ID] = end
+		}
+		list[i].EndTime = end
+	}
+
+	return nil
+}
+
+// instanceDuration returns how long the instance ran for, or has been
+// running for if it has not ended.
+func instanceDuration(in *client.Instance, now time.Time) time.Duration {
+	if in.EndTime != nil {
+		return in.EndTime.Sub(in.BeginTime)
+	}
+	return now.Sub(in.BeginTime)
+}
+
+// formatTime formats t in the local time zone for tables.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(timeLayout)
+}
+
+// instanceView is an instance as shown by instances get, with its input and
+// output embedded as JSON rather than encoded as base64.
+type instanceView struct {
+	*client.Instance
+	Duration string      `json:"duration"`
+	Input    interface{} `json:"input,omitempty"`
+	Output   interface{} `json:"output,omitempty"`
+}
+
+func newInstanceView(in *client.Instance, now time.Time) *instanceView {
+	return &instanceView{
+		Instance: in,
+		Duration: instanceDuration(in, now).Round(time.Millisecond).String(),
+		Input:    embedJSON(in.Input),
+		Output:   embedJSON(in.Output),
+	}
+}
+
+// embedJSON returns b as is if it is valid JSON, as a string otherwise, or
+// nil if it is empty.
+func embedJSON(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	if json.Valid(b) {
+		return json.RawMessage(b)
+	}
+	return string(b)
+}
+
+// writeInstance writes the human readable view of an instance. Input and
+// output are indented unless raw is set.
+func writeInstance(w io.Writer, v *instanceView, raw bool) error {
+	in := v.Instance
+
+	fields := [][2]string{
+		{"ID", in.ID},
+		{"Workflow", in.Workflow()},
+		{"Revision", fmt.Sprintf("%d", in.Revision)},
+		{"Invoked By", in.InvokedBy},
+		{"Status", in.Status},
+		{"Begin", formatTime(in.BeginTime)},
+	}
+
+	if in.EndTime != nil {
+		fields = append(fields, [2]string{"End", formatTime(*in.EndTime)})
+	}
+	fields = append(fields, [2]string{"Duration", v.Duration})
+
+	if in.ErrorCode != "" || in.ErrorMessage != "" {
+		fields = append(fields, [2]string{"Error Code", in.ErrorCode}, [2]string{"Error Message", in.ErrorMessage})
+	}
+
+	if len(in.Flow) > 0 {
+		fields = append(fields, [2]string{"Flow", strings.Join(in.Flow, " -> ")})
+	}
+
+	for _, f := range fields {
+		_, err := fmt.Fprintf(w, "%-15s%s\n", f[0]+":", f[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{"Input", in.Input}, {"Output", in.Output}} {
+		_, err := fmt.Fprintf(w, "%s:\n%s\n", f.name, formatJSON(f.data, raw))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatJSON indents b for display unless raw is set or it is not valid
+// JSON, in which case it is returned as is.
+func formatJSON(b []byte, raw bool) string {
+	if len(b) == 0 {
+		return "  <none>"
+	}
+
+	if raw {
+		return string(b)
+	}
+
+	var buf bytes.Buffer
+	if json.Indent(&buf, b, "  ", "  ") != nil {
+		return string(b)
+	}
+	return "  " + buf.String()
+}
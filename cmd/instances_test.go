@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vorteil/direkcli/client"
+)
+
+func TestCheckInstanceSort(t *testing.T) {
+	tests := []struct {
+		sort string
+		err  bool
+	}{
+		{sort: ""},
+		{sort: "id"},
+		{sort: "-begin"},
+		{sort: "duration"},
+		{sort: "-end"},
+		{sort: "name", err: true},
+		{sort: "-", err: true},
+		{sort: "--id", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			flagListSort = tt.sort
+			defer func() { flagListSort = "" }()
+
+			err := checkInstanceSort()
+			if tt.err != (err != nil) {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2021, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+		err   bool
+	}{
+		{value: "2021-02-01T10:30:00Z", want: time.Date(2021, 2, 1, 10, 30, 0, 0, time.UTC)},
+		{value: "2021-02-01T10:30:00+02:00", want: time.Date(2021, 2, 1, 8, 30, 0, 0, time.UTC)},
+		{value: "2021-02-01", want: time.Date(2021, 2, 1, 0, 0, 0, 0, time.Local)},
+		{value: "24h", want: now.Add(-24 * time.Hour)},
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "yesterday", err: true},
+		{value: "2021-02-31", err: true},
+		{value: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTime("since", tt.value, now)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstanceFilter(t *testing.T) {
+	now := time.Date(2021, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		status   []string
+		workflow string
+		since    string
+		until    string
+		want     *client.InstanceFilter
+		err      bool
+	}{
+		{name: "none", want: &client.InstanceFilter{}},
+		{name: "statuses", status: []string{"failed", "Crashed"}, want: &client.InstanceFilter{Statuses: []string{"failed", "crashed"}}},
+		{name: "workflow", workflow: "wf", want: &client.InstanceFilter{Workflow: "wf"}},
+		{name: "times", since: "24h", until: "2021-02-03T11:00:00Z", want: &client.InstanceFilter{
+			Since: now.Add(-24 * time.Hour),
+			Until: time.Date(2021, 2, 3, 11, 0, 0, 0, time.UTC),
+		}},
+		{name: "unknown status", status: []string{"running"}, err: true},
+		{name: "invalid since", since: "soon", err: true},
+		{name: "invalid until", until: "later", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagListStatus, flagListWorkflow, flagListSince, flagListUntil = tt.status, tt.workflow, tt.since, tt.until
+			defer func() {
+				flagListStatus, flagListWorkflow, flagListSince, flagListUntil = nil, "", "", ""
+			}()
+
+			f, err := instanceFilter(now)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", f)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !f.Since.Equal(tt.want.Since) || !f.Until.Equal(tt.want.Until) {
+				t.Fatalf("got %v to %v, want %v to %v", f.Since, f.Until, tt.want.Since, tt.want.Until)
+			}
+			if !reflect.DeepEqual(f.Statuses, tt.want.Statuses) || f.Workflow != tt.want.Workflow {
+				t.Fatalf("got %+v, want %+v", f, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageInstances(t *testing.T) {
+	now := time.Date(2021, 2, 3, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return now.Add(time.Duration(minutes) * time.Minute)
+	}
+	end := func(minutes int) *time.Time {
+		t := at(minutes)
+		return &t
+	}
+
+	// begin order c, a, d, b; durations a 10m, b 2m, c running for 60m, d 5m
+	instances := func() []*client.Instance {
+		return []*client.Instance{
+			{ID: "ns/x/a", Status: client.StatusComplete, BeginTime: at(-50), EndTime: end(-40)},
+			{ID: "ns/y/b", Status: client.StatusFailed, BeginTime: at(-10), EndTime: end(-8)},
+			{ID: "ns/x/c", Status: client.StatusPending, BeginTime: at(-60)},
+			{ID: "ns/y/d", Status: client.StatusComplete, BeginTime: at(-30), EndTime: end(-25)},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		sort   string
+		offset int
+		limit  int
+		want   []string
+	}{
+		{name: "unsorted", want: []string{"ns/x/a", "ns/y/b", "ns/x/c", "ns/y/d"}},
+		{name: "id", sort: "id", want: []string{"ns/x/a", "ns/y/b", "ns/x/c", "ns/y/d"}},
+		{name: "id descending", sort: "-id", want: []string{"ns/y/d", "ns/x/c", "ns/y/b", "ns/x/a"}},
+		{name: "workflow is stable", sort: "workflow", want: []string{"ns/x/a", "ns/x/c", "ns/y/b", "ns/y/d"}},
+		{name: "status", sort: "status", want: []string{"ns/x/a", "ns/y/d", "ns/y/b", "ns/x/c"}},
+		{name: "begin", sort: "begin", want: []string{"ns/x/c", "ns/x/a", "ns/y/d", "ns/y/b"}},
+		{name: "end running last", sort: "end", want: []string{"ns/x/a", "ns/y/d", "ns/y/b", "ns/x/c"}},
+		{name: "end descending running first", sort: "-end", want: []string{"ns/x/c", "ns/y/b", "ns/y/d", "ns/x/a"}},
+		{name: "duration", sort: "duration", want: []string{"ns/y/b", "ns/y/d", "ns/x/a", "ns/x/c"}},
+		{name: "offset after sort", sort: "begin", offset: 1, want: []string{"ns/x/a", "ns/y/d", "ns/y/b"}},
+		{name: "limit after sort", sort: "-begin", limit: 2, want: []string{"ns/y/b", "ns/y/d"}},
+		{name: "offset and limit", sort: "begin", offset: 1, limit: 2, want: []string{"ns/x/a", "ns/y/d"}},
+		{name: "limit past end", limit: 10, want: []string{"ns/x/a", "ns/y/b", "ns/x/c", "ns/y/d"}},
+		{name: "offset at end", offset: 4, want: []string{}},
+		{name: "offset past end", sort: "id", offset: 10, limit: 2, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagListSort, flagListOffset, flagListLimit = tt.sort, tt.offset, tt.limit
+			defer func() {
+				flagListSort, flagListOffset, flagListLimit = "", 0, 0
+			}()
+
+			list := pageInstances(instances(), now)
+			if list == nil {
+				t.Fatal("got nil, want an empty list")
+			}
+
+			ids := []string{}
+			for _, in := range list {
+				ids = append(ids, in.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Fatalf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
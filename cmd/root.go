@@ -1,28 +1,72 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
-	"github.com/sisatech/tablewriter"
+	"github.com/mattn/go-isatty"
 	cobra "github.com/spf13/cobra"
-	"github.com/vorteil/direkcli/pkg/instance"
+	"github.com/vorteil/direkcli/client"
+	"github.com/vorteil/direkcli/pkg/config"
+	"github.com/vorteil/direkcli/pkg/definition"
 	log "github.com/vorteil/direkcli/pkg/log"
-	"github.com/vorteil/direkcli/pkg/namespace"
-	store "github.com/vorteil/direkcli/pkg/store"
-	"github.com/vorteil/direkcli/pkg/workflow"
-	"github.com/vorteil/direktiv/pkg/ingress"
+	"github.com/vorteil/direkcli/pkg/output"
 	"github.com/vorteil/vorteil/pkg/elog"
-	"google.golang.org/grpc"
 )
 
 var flagInputFile string
+var flagFollow bool
+var flagWait bool
+var flagWaitLogs bool
+var flagAllRunning string
+var flagInputOverride string
+var flagWatch bool
+var flagWatchInterval time.Duration
+var flagCancelWorkflow string
+var flagYes bool
+var flagInterval time.Duration
+var flagLogsOffset int
+var flagLogsLimit int
+var flagLogsTail int
+var flagLogsAll bool
+var flagLogsFormat string
+var flagLogsTimestamps bool
 var flagGRPC string
-
-var conn *grpc.ClientConn
+var flagProfile string
+var flagConfig string
+var flagTLS bool
+var flagCACert string
+var flagClientCert string
+var flagClientKey string
+var flagInsecureSkipVerify bool
+var flagToken string
+var flagOutput string
+var flagTimeout string
+var flagRetries int
+var flagRetryBackoff string
+var flagRetryMaxBackoff string
+var flagRetryTimeout string
+var flagRetryCodes string
+var flagTokenFile string
+var flagTokenHeader string
+var flagTokenScheme string
+
+var api *client.Client
 var logger elog.View
-var grpcConnection = "127.0.0.1:6666"
+var cfg *config.Config
+var profile *config.Profile
+var printer output.Printer
 
 func generateCmd(use, short, long string, fn func(cmd *cobra.Command, args []string), c cobra.PositionalArgs) *cobra.Command {
 	return &cobra.Command{
@@ -34,338 +78,768 @@ func generateCmd(use, short, long string, fn func(cmd *cobra.Command, args []str
 	}
 }
 
+// namespaceArgs accepts n positional arguments, or n-1 if the leading
+// NAMESPACE argument is to be taken from the active profile.
+func namespaceArgs(n int) cobra.PositionalArgs {
+	return cobra.RangeArgs(n-1, n)
+}
+
+// withNamespace prepends the default namespace of the active profile to args
+// if the NAMESPACE argument was omitted.
+func withNamespace(args []string, n int) []string {
+	if len(args) == n {
+		return args
+	}
+
+	if profile.Namespace == "" {
+		logger.Errorf("no namespace provided and no default namespace configured")
+		os.Exit(exitUsage)
+	}
+
+	return append([]string{profile.Namespace}, args...)
+}
+
+// confirm asks the user a yes/no question on stderr, returning true only if
+// they answer yes. It returns false if stdin is closed.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// stdinIsTerminal reports whether stdin is a terminal, and so whether a
+// question asked with confirm can be answered.
+func stdinIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
+
+// waitForInstance waits for the instance to finish, printing its output to
+// stdout and, if logs is set, its logs to stderr while it runs. It exits
+// with exitInstanceFailed if the instance did not complete.
+func waitForInstance(cmd *cobra.Command, id string, logs bool) {
+	var in *client.Instance
+	var err error
+
+	if logs {
+		lw, lerr := output.NewLogWriter(os.Stderr, output.LogFormatText, false)
+		if lerr != nil {
+			fail(lerr)
+		}
+
+		err = api.FollowLogs(cmd.Context(), id, nil, flagInterval, lw.Write)
+		if err == nil {
+			in, err = api.GetInstance(cmd.Context(), id)
+		}
+	} else {
+		in, err = api.WaitInstance(cmd.Context(), id, flagInterval)
+	}
+
+	if err != nil {
+		if cmd.Context().Err() != nil {
+			logger.Warnf("Stopped waiting, instance '%s' is still running", id)
+			os.Exit(exitError)
+		}
+		fail(err)
+	}
+
+	if len(in.Output) > 0 {
+		fmt.Println(string(in.Output))
+	}
+
+	if in.Status != client.StatusComplete {
+		if in.ErrorCode != "" || in.ErrorMessage != "" {
+			logger.Errorf("Instance '%s' %s: [%s] %s", id, in.Status, in.ErrorCode, in.ErrorMessage)
+		} else {
+			logger.Errorf("Instance '%s' %s", id, in.Status)
+		}
+		os.Exit(exitInstanceFailed)
+	}
+}
+
+// printResult renders r to stdout in the format selected with --output. If
+// there is nothing to show in a table the empty message is logged instead.
+func printResult(r *output.Result, empty string) {
+	if printer.Tabular() && r.Text == nil && len(r.Items) == 0 && empty != "" {
+		logger.Printf("%s", empty)
+		return
+	}
+
+	err := printer.Print(os.Stdout, r)
+	if err != nil {
+		fail(err)
+	}
+}
+
+// loadConfig reads the config file from the --config flag, DIREKCLI_CONFIG
+// or the default location.
+func loadConfig() error {
+	var err error
+
+	path := flagConfig
+	if path == "" {
+		path, err = config.DefaultPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	cfg, err = config.Load(path)
+	return err
+}
+
+// loadProfile resolves the active profile, applying environment variable
+// and command line overrides on top of the config file.
+func loadProfile() error {
+	err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	profile, err = cfg.Resolve(flagProfile)
+	if err != nil {
+		return err
+	}
+
+	if flagGRPC != "" {
+		profile.Address = flagGRPC
+	}
+	if flagTimeout != "" {
+		profile.Timeout = flagTimeout
+	}
+	if flagRetries >= 0 {
+		profile.Retries = &flagRetries
+	}
+	if flagRetryBackoff != "" {
+		profile.RetryBackoff = flagRetryBackoff
+	}
+	if flagRetryMaxBackoff != "" {
+		profile.RetryMaxBackoff = flagRetryMaxBackoff
+	}
+	if flagRetryTimeout != "" {
+		profile.RetryTimeout = flagRetryTimeout
+	}
+	if flagRetryCodes != "" {
+		profile.RetryCodes = flagRetryCodes
+	}
+	if flagTLS {
+		profile.TLS = true
+	}
+	if flagCACert != "" {
+		profile.CACert = flagCACert
+	}
+	if flagClientCert != "" {
+		profile.ClientCert = flagClientCert
+	}
+	if flagClientKey != "" {
+		profile.ClientKey = flagClientKey
+	}
+	if flagInsecureSkipVerify {
+		profile.InsecureSkipVerify = true
+	}
+	if flagTokenFile != "" {
+		profile.Token = ""
+		profile.TokenFile = flagTokenFile
+	}
+	if flagToken != "" {
+		profile.Token = flagToken
+	}
+	if flagTokenHeader != "" {
+		profile.TokenHeader = flagTokenHeader
+	}
+	if flagTokenScheme != "" {
+		profile.TokenScheme = flagTokenScheme
+	}
+
+	return nil
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "direkcli",
 	Short: "A CLI for interacting with a direktiv server via gRPC.",
-	Long:  ``,
+	Long:  exitCodesHelp,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		logger = log.GetLogger()
 		var err error
-		connF, err := cmd.Flags().GetString("grpc")
+		printer, err = output.NewPrinter(flagOutput)
 		if err != nil {
 			return err
 		}
-		if connF == "" {
-			connF = grpcConnection
+
+		err = loadProfile()
+		if err != nil {
+			return setupFailed(cmd, err)
 		}
 
-		conn, err = grpc.Dial(connF, grpc.WithInsecure())
+		clientConfig, err := profile.ClientConfig()
 		if err != nil {
-			return err
+			return setupFailed(cmd, err)
+		}
+
+		// only a token given on the command line is an error without TLS,
+		// one stored on the profile or taken from DIREKCLI_TOKEN is left out
+		// so that it does not break every command against a local server
+		if clientConfig.Token != "" && !clientConfig.UsesTLS() && flagToken == "" && flagTokenFile == "" {
+			logger.Warnf("Not sending the token over a plaintext connection, enable TLS to use it")
+			clientConfig.Token = ""
+		}
+
+		api, err = client.Dial(clientConfig)
+		if err != nil {
+			return setupFailed(cmd, err)
 		}
 
 		return nil
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// commands using configPreRunE never connect
+		if api != nil {
+			api.Close()
+		}
+	},
 }
 
 // namespaceCmd
 var namespaceCmd = generateCmd("namespaces", "List, create and delete namespaces", "", nil, nil)
 
 // namespaceSendEventCmd
-var namespaceSendEventCmd = generateCmd("send NAMESPACE CLOUDEVENTPATH", "Send a cloud event to a namespace", "", func(cmd *cobra.Command, args []string) {
-	success, err := namespace.SendEvent(conn, args[0], args[1])
+var namespaceSendEventCmd = generateCmd("send [NAMESPACE] CLOUDEVENTPATH", "Send a cloud event to a namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
+
+	// read Cloud Event file
+	event, err := ioutil.ReadFile(args[1])
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
 
-// namespaceListCmd
-var namespaceListCmd = generateCmd("list", "Returns a list of namespaces", "", func(cmd *cobra.Command, args []string) {
-
-	list, err := namespace.List(conn)
+	err = api.SendEvent(cmd.Context(), args[0], event)
 	if err != nil {
-		logger.Errorf("%s", err.Error())
-		os.Exit(1)
-	}
-	if len(list) == 0 {
-		logger.Printf("No namespaces exist")
-		return
+		fail(err)
 	}
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Name"})
+	logger.Printf("Successfully sent event to '%s'", args[0])
+}, namespaceArgs(2))
 
-	for _, namespace := range list {
-		table.Append([]string{
-			namespace.GetName(),
-		})
-	}
+// namespaceListCmd
+var namespaceListCmd = generateCmd("list", "Returns a list of namespaces", "", func(cmd *cobra.Command, args []string) {
 
-	table.Render()
+	list, err := api.ListNamespaces(cmd.Context())
+	if err != nil {
+		fail(err)
+	}
+
+	items := make([]interface{}, len(list))
+	for i := range list {
+		items[i] = list[i]
+	}
+
+	printResult(&output.Result{
+		Object: &struct {
+			Namespaces []*client.Namespace `json:"namespaces"`
+		}{list},
+		Items: items,
+		Columns: []output.Column{
+			{Header: "Name", Value: func(item interface{}) string {
+				return item.(*client.Namespace).Name
+			}},
+		},
+		Name: func(item interface{}) string {
+			return item.(*client.Namespace).Name
+		},
+	}, "No namespaces exist")
 }, cobra.ExactArgs(0))
 
 // namespaceCreateCmd
 var namespaceCreateCmd = generateCmd("create NAMESPACE", "Create a new namespace", "", func(cmd *cobra.Command, args []string) {
-	success, err := namespace.Create(args[0], conn)
+	ns, err := api.CreateNamespace(cmd.Context(), args[0])
 	if err != nil {
-		logger.Errorf("%s", err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf(success)
+	logger.Printf("Created namespace: %s", ns.Name)
 }, cobra.ExactArgs(1))
 
 // namespaceDeleteCmd
 var namespaceDeleteCmd = generateCmd("delete NAMESPACE", "Deletes a namespace", "", func(cmd *cobra.Command, args []string) {
-	success, err := namespace.Delete(args[0], conn)
+	err := api.DeleteNamespace(cmd.Context(), args[0])
 	if err != nil {
-		logger.Errorf("%s", err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf(success)
+	logger.Printf("Deleted namespace: %s", args[0])
 }, cobra.ExactArgs(1))
 
 // workflowCmd
 var workflowCmd = generateCmd("workflows", "List, create, get and execute workflows", "", nil, nil)
 
 // workflowListCmd
-var workflowListCmd = generateCmd("list NAMESPACE", "List all workflows under a namespace", "", func(cmd *cobra.Command, args []string) {
-
-	list, err := workflow.List(conn, args[0])
-	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
-	}
+var workflowListCmd = generateCmd("list [NAMESPACE]", "List all workflows under a namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 1)
 
-	if len(list) == 0 {
-		logger.Printf("No workflows exist under '%s'", args[0])
+	if flagWatch {
+		watchResults(cmd.Context(), func() (*output.Result, error) {
+			return workflowListResult(cmd.Context(), args[0])
+		})
 		return
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID"})
-
-	// Build string array rows
-	for _, wf := range list {
-		table.Append([]string{
-			wf.GetId(),
-		})
+	r, err := workflowListResult(cmd.Context(), args[0])
+	if err != nil {
+		fail(err)
 	}
-	table.Render()
-}, cobra.ExactArgs(1))
+	printResult(r, fmt.Sprintf("No workflows exist under '%s'", args[0]))
+}, namespaceArgs(1))
+
+// workflowListResult lists the workflows in a namespace for printing.
+func workflowListResult(ctx context.Context, namespace string) (*output.Result, error) {
+	list, err := api.ListWorkflows(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, len(list))
+	for i := range list {
+		items[i] = list[i]
+	}
+
+	return &output.Result{
+		Object: &struct {
+			Workflows []*client.Workflow `json:"workflows"`
+		}{list},
+		Items: items,
+		Columns: []output.Column{
+			{Header: "ID", Value: func(item interface{}) string {
+				return item.(*client.Workflow).ID
+			}},
+			{Header: "Revision", Wide: true, Value: func(item interface{}) string {
+				return fmt.Sprintf("%d", item.(*client.Workflow).Revision)
+			}},
+			{Header: "Active", Wide: true, Value: func(item interface{}) string {
+				return fmt.Sprintf("%t", item.(*client.Workflow).Active)
+			}},
+			{Header: "Description", Wide: true, Value: func(item interface{}) string {
+				return item.(*client.Workflow).Description
+			}},
+		},
+		Name: func(item interface{}) string {
+			return item.(*client.Workflow).ID
+		},
+		List: "workflows",
+	}, nil
+}
 
 // workflowGetCmd
-var workflowGetCmd = generateCmd("get NAMESPACE ID", "Get YAML of a workflow", "", func(cmd *cobra.Command, args []string) {
-	success, err := workflow.Get(conn, args[0], args[1])
+var workflowGetCmd = generateCmd("get [NAMESPACE] ID", "Get YAML of a workflow", `Prints the YAML definition of the workflow. With -o json or -o yaml the
+workflow is printed with its definition as a string.`, func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
+	wf, err := api.GetWorkflow(cmd.Context(), args[0], args[1])
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
-	}
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
+		fail(err)
+	}
+
+	printResult(&output.Result{
+		Object: &struct {
+			*client.Workflow
+			Definition string `json:"definition"`
+		}{wf, string(wf.Definition)},
+		Items: []interface{}{wf},
+		Name: func(item interface{}) string {
+			return item.(*client.Workflow).ID
+		},
+		Text: func(w io.Writer) error {
+			_, err := w.Write(wf.Definition)
+			if err == nil && !bytes.HasSuffix(wf.Definition, []byte("\n")) {
+				_, err = fmt.Fprintln(w)
+			}
+			return err
+		},
+	}, "")
+}, namespaceArgs(2))
 
 // workflowExecuteCmd
-var workflowExecuteCmd = generateCmd("execute NAMESPACE ID", "Executes workflow with provided ID", "", func(cmd *cobra.Command, args []string) {
-	input, err := cmd.Flags().GetString("input")
+var workflowExecuteCmd = generateCmd("execute [NAMESPACE] ID", "Executes workflow with provided ID", inputHelp, func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
+
+	b, err := readInput()
 	if err != nil {
-		logger.Errorf("unable to retrieve input flag")
-		os.Exit(1)
+		logger.Errorf("%v", err)
+		os.Exit(exitUsage)
 	}
 
-	success, err := workflow.Execute(conn, args[0], args[1], input)
+	id, err := api.InvokeWorkflow(cmd.Context(), args[0], args[1], b)
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
 
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
+	logger.Printf("Successfully invoked, Instance ID: %s", id)
+
+	if flagWait || flagWaitLogs {
+		waitForInstance(cmd, id, flagWaitLogs)
+	}
+}, namespaceArgs(2))
 
-var workflowToggleCmd = generateCmd("toggle NAMESPACE WORKFLOW", "Enables or disables the workflow provided", "", func(cmd *cobra.Command, args []string) {
-	success, err := workflow.Toggle(conn, args[0], args[1])
+var workflowToggleCmd = generateCmd("toggle [NAMESPACE] WORKFLOW", "Enables or disables the workflow provided", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
+	wf, err := api.ToggleWorkflow(cmd.Context(), args[0], args[1])
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
+
+	if wf.Active {
+		logger.Printf("Enabled workflow '%s'", args[1])
+	} else {
+		logger.Printf("Disabled workflow '%s'", args[1])
+	}
+}, namespaceArgs(2))
 
 // workflowAddCmd
-var workflowAddCmd = generateCmd("create NAMESPACE WORKFLOW", "Creates a new workflow on provided namespace", "", func(cmd *cobra.Command, args []string) {
+var workflowAddCmd = generateCmd("create [NAMESPACE] WORKFLOW", "Creates a new workflow on provided namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
 	// args[0] should be namespace, args[1] should be path to the workflow file
-	success, err := workflow.Add(conn, args[0], args[1])
+	b, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		fail(err)
+	}
+
+	wf, err := api.CreateWorkflow(cmd.Context(), args[0], b)
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
+	logger.Printf("Created workflow '%s'", wf.ID)
+}, namespaceArgs(2))
 
 // workflowUpdateCmd
-var workflowUpdateCmd = generateCmd("update NAMESPACE ID WORKFLOW", "Updates an existing workflow", "", func(cmd *cobra.Command, args []string) {
-	success, err := workflow.Update(conn, args[0], args[1], args[2])
+var workflowUpdateCmd = generateCmd("update [NAMESPACE] ID WORKFLOW", "Updates an existing workflow", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 3)
+	b, err := ioutil.ReadFile(args[2])
+	if err != nil {
+		fail(err)
+	}
+
+	wf, err := api.UpdateWorkflow(cmd.Context(), args[0], args[1], b)
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(3))
+	logger.Printf("Successfully updated '%s'", wf.ID)
+}, namespaceArgs(3))
 
 // workflowDeleteCmd
-var workflowDeleteCmd = generateCmd("delete NAMESPACE ID", "Deletes an existing workflow", "", func(cmd *cobra.Command, args []string) {
-	success, err := workflow.Delete(conn, args[0], args[1])
+var workflowDeleteCmd = generateCmd("delete [NAMESPACE] ID", "Deletes an existing workflow", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
+	err := api.DeleteWorkflow(cmd.Context(), args[0], args[1])
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
+	logger.Printf("Deleted workflow '%v'", args[1])
+}, namespaceArgs(2))
 
 // instanceCmd
-var instanceCmd = generateCmd("instances", "List, get and retrieve logs for instances", "", nil, nil)
+var instanceCmd = generateCmd("instances", "List, get, cancel, rerun and retrieve logs for instances", "", nil, nil)
 
 var instanceGetCmd = generateCmd("get ID", "Get details about a workflow instance", "", func(cmd *cobra.Command, args []string) {
-	resp, err := instance.Get(conn, args[0])
+	in, err := api.GetInstance(cmd.Context(), args[0])
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
-	logger.Printf("ID: %s", resp.GetId())
-	logger.Printf("Input: %s", string(resp.GetInput()))
-	logger.Printf("Output: %s", string(resp.GetOutput()))
+
+	v := newInstanceView(in, time.Now())
+
+	printResult(&output.Result{
+		Object: v,
+		Items:  []interface{}{in},
+		Name: func(item interface{}) string {
+			return item.(*client.Instance).ID
+		},
+		Text: func(w io.Writer) error {
+			return writeInstance(w, v, flagRaw)
+		},
+	}, "")
 }, cobra.ExactArgs(1))
 
-var instanceLogsCmd = generateCmd("logs ID", "Grabs all logs for the instance ID provided", "", func(cmd *cobra.Command, args []string) {
-	logs, err := instance.Logs(conn, args[0])
+var instanceRerunCmd = generateCmd("rerun ID", "Executes the workflow of an instance again with the same input", "", func(cmd *cobra.Command, args []string) {
+	namespace, wf, err := client.ParseInstanceID(args[0])
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
-	}
-	for _, log := range logs {
-		fmt.Printf("%s", log.GetMessage())
+		fail(err)
 	}
-}, cobra.ExactArgs(1))
 
-var instanceListCmd = generateCmd("list NAMESPACE", "List all workflow instances from the provided namespace", "", func(cmd *cobra.Command, args []string) {
-	list, err := instance.List(conn, args[0])
+	in, err := api.GetInstance(cmd.Context(), args[0])
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
 
-	if len(list) == 0 {
-		logger.Printf("No instances exist under '%s'", args[0])
-		return
+	input := in.Input
+	if flagInputOverride != "" {
+		if flagInputOverride == "-" {
+			input, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			input, err = ioutil.ReadFile(flagInputOverride)
+		}
+		if err != nil {
+			fail(err)
+		}
+
+		if !json.Valid(input) {
+			logger.Errorf("input is not valid JSON")
+			os.Exit(exitUsage)
+		}
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID", "Status"})
+	id, err := api.InvokeWorkflow(cmd.Context(), namespace, wf, input)
+	if err != nil {
+		fail(err)
+	}
 
-	// Build string array rows
-	for _, instance := range list {
-		table.Append([]string{
-			instance.GetId(),
-			instance.GetStatus(),
-		})
+	logger.Printf("Successfully invoked, Instance ID: %s", id)
+
+	if flagWait || flagWaitLogs {
+		waitForInstance(cmd, id, flagWaitLogs)
 	}
-	table.Render()
 }, cobra.ExactArgs(1))
 
-//registriesCmd
-var registriesCmd = generateCmd("registries", "List, create and remove registries from provided namespace", "", nil, nil)
+var instanceLogsCmd = generateCmd("logs ID", "Grabs all logs for the instance ID provided", "", func(cmd *cobra.Command, args []string) {
+	lw, err := output.NewLogWriter(os.Stdout, flagLogsFormat, flagLogsTimestamps)
+	if err != nil {
+		fail(err)
+	}
 
-var createRegistryCmd = generateCmd("create NAMESPACE URL USER:TOKEN", "Creates a new registry on provided namespace", "", func(cmd *cobra.Command, args []string) {
-	// replace : with a ! for args[2] ! is used in direktiv ! gets picked up by bash unfortunately
-	args[2] = strings.ReplaceAll(args[2], ":", "!")
-	storeV := store.StoreRequest{
-		Key:   args[1],
-		Value: args[2],
+	opts := &client.LogOptions{
+		Offset: flagLogsOffset,
+		Limit:  flagLogsLimit,
+		Tail:   flagLogsTail,
+	}
+	// --tail looks at every entry unless a limit is given explicitly
+	if flagLogsAll || (opts.Tail > 0 && !cmd.Flags().Changed("limit")) {
+		opts.Limit = 0
 	}
 
-	success, err := store.Create(conn, args[0], &storeV, "registry")
-	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+	if flagFollow {
+		err = api.FollowLogs(cmd.Context(), args[0], opts, flagInterval, lw.Write)
+		// stopping with Ctrl-C is not an error
+		if err != nil && cmd.Context().Err() == nil {
+			fail(err)
+		}
+		return
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(3))
 
-var removeRegistryCmd = generateCmd("delete NAMESPACE URL", "Deletes a registry from the provided namespace", "", func(cmd *cobra.Command, args []string) {
-	success, err := store.Delete(conn, args[0], args[1], "registry")
+	logs, err := api.InstanceLogs(cmd.Context(), args[0], opts)
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
+	}
+	for _, log := range logs {
+		err = lw.Write(log)
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	if opts.Limit > 0 && opts.Tail == 0 && len(logs) == opts.Limit {
+		logger.Warnf("Output limited to %d entries, use --all or --limit to show more", opts.Limit)
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
+}, cobra.ExactArgs(1))
 
-var listRegistriesCmd = generateCmd("list NAMESPACE", "Returns a list of registries from the provided namespace", "", func(cmd *cobra.Command, args []string) {
-	registries, err := store.List(conn, args[0], "registry")
+var instanceCancelCmd = generateCmd("cancel [ID]", "Cancels a running workflow instance", "With --all-running every pending instance in the namespace is cancelled,\nor only those of the workflow given by --workflow, after asking for\nconfirmation, which requires --yes if stdin is not a terminal.", func(cmd *cobra.Command, args []string) {
+	if flagAllRunning == "" {
+		err := api.CancelInstance(cmd.Context(), args[0])
+		if err != nil {
+			fail(err)
+		}
+		logger.Printf("Cancelled instance '%s'", args[0])
+		return
+	}
+
+	list, err := api.ListInstances(cmd.Context(), flagAllRunning)
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
+	}
+
+	var ids []string
+	for _, in := range list {
+		if in.Status != client.StatusPending {
+			continue
+		}
+		if flagCancelWorkflow != "" && in.Workflow() != flagCancelWorkflow {
+			continue
+		}
+		ids = append(ids, in.ID)
 	}
-	castRegistries := registries.([]*ingress.GetRegistriesResponse_Registry)
 
-	if len(castRegistries) == 0 {
-		logger.Printf("No registries exist under '%s'", args[0])
+	if len(ids) == 0 {
+		logger.Printf("No running instances to cancel under '%s'", flagAllRunning)
 		return
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Registry"})
+	if !flagYes {
+		if !stdinIsTerminal() {
+			logger.Errorf("refusing to cancel without --yes as stdin is not a terminal")
+			os.Exit(exitUsage)
+		}
 
-	// Build string array rows
-	for _, registry := range castRegistries {
-		table.Append([]string{
-			registry.GetName(),
-		})
+		for _, id := range ids {
+			fmt.Fprintln(os.Stderr, id)
+		}
+		if !confirm(fmt.Sprintf("Cancel %d instances?", len(ids))) {
+			logger.Errorf("Aborted")
+			os.Exit(exitError)
+		}
 	}
-	table.Render()
-}, cobra.ExactArgs(1))
 
-//secretsCmd
-var secretsCmd = generateCmd("secrets", "List, create and delete secrets from the provided namespace", "", nil, nil)
+	var last error
+	for _, id := range ids {
+		err = api.CancelInstance(cmd.Context(), id)
+		if err != nil {
+			logger.Errorf("Unable to cancel instance '%s': %v", id, err)
+			last = err
+			continue
+		}
+		logger.Printf("Cancelled instance '%s'", id)
+	}
 
-var createSecretCmd = generateCmd("create NAMESPACE KEY VALUE", "Creates a new secret on the provided namespace", "", func(cmd *cobra.Command, args []string) {
-	storeV := store.StoreRequest{
-		Key:   args[1],
-		Value: args[2],
+	if last != nil {
+		os.Exit(exitCode(last))
+	}
+}, func(cmd *cobra.Command, args []string) error {
+	if flagAllRunning != "" {
+		return cobra.NoArgs(cmd, args)
 	}
+	return cobra.ExactArgs(1)(cmd, args)
+})
+
+var instanceListCmd = generateCmd("list [NAMESPACE]", "List all workflow instances from the provided namespace", instanceListHelp, func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 1)
 
-	successMsg, err := store.Create(conn, args[0], &storeV, "secret")
+	f, err := instanceFilter(time.Now())
+	if err == nil {
+		err = checkInstanceSort()
+	}
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		logger.Errorf("%v", err)
+		os.Exit(exitUsage)
 	}
-	logger.Printf(successMsg)
-}, cobra.ExactArgs(3))
 
-var removeSecretCmd = generateCmd("delete NAMESPACE KEY", "Deletes a secret from the provided namespace", "", func(cmd *cobra.Command, args []string) {
-	success, err := store.Delete(conn, args[0], args[1], "secret")
-	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+	if flagWatch {
+		watchResults(cmd.Context(), func() (*output.Result, error) {
+			return instanceListResult(cmd.Context(), args[0], f, time.Now())
+		})
+		return
 	}
-	logger.Printf(success)
-}, cobra.ExactArgs(2))
 
-var listSecretsCmd = generateCmd("list NAMESPACE", "Returns a list of secrets for the provided namespace", "", func(cmd *cobra.Command, args []string) {
-	secrets, err := store.List(conn, args[0], "secret")
+	r, err := instanceListResult(cmd.Context(), args[0], f, time.Now())
 	if err != nil {
-		logger.Errorf(err.Error())
-		os.Exit(1)
+		fail(err)
 	}
 
-	castSecrets := secrets.([]*ingress.GetSecretsResponse_Secret)
+	empty := fmt.Sprintf("No instances exist under '%s'", args[0])
+	if !f.IsZero() || flagListOffset > 0 {
+		empty = fmt.Sprintf("No matching instances exist under '%s'", args[0])
+	}
+	printResult(r, empty)
+}, namespaceArgs(1))
 
-	if len(castSecrets) == 0 {
-		logger.Printf("No secrets exist under '%s'", args[0])
-		return
+//registriesCmd
+var registriesCmd = generateCmd("registries", "List, create and remove registries from provided namespace", "", nil, nil)
+
+var createRegistryCmd = generateCmd("create [NAMESPACE] URL USER:TOKEN", "Creates a new registry on provided namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 3)
+	// replace : with a ! for args[2] ! is used in direktiv ! gets picked up by bash unfortunately
+	args[2] = strings.ReplaceAll(args[2], ":", "!")
+
+	err := api.CreateRegistry(cmd.Context(), args[0], args[1], args[2])
+	if err != nil {
+		fail(err)
 	}
+	logger.Printf("Successfully created registry '%s'.", args[1])
+}, namespaceArgs(3))
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Secret"})
+var removeRegistryCmd = generateCmd("delete [NAMESPACE] URL", "Deletes a registry from the provided namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
+	err := api.DeleteRegistry(cmd.Context(), args[0], args[1])
+	if err != nil {
+		fail(err)
+	}
+	logger.Printf("Successfully removed registry '%s'.", args[1])
+}, namespaceArgs(2))
 
-	// Build string array rows
-	for _, secret := range castSecrets {
-		table.Append([]string{
-			secret.GetName(),
-		})
+var listRegistriesCmd = generateCmd("list [NAMESPACE]", "Returns a list of registries from the provided namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 1)
+	registries, err := api.ListRegistries(cmd.Context(), args[0])
+	if err != nil {
+		fail(err)
+	}
+
+	items := make([]interface{}, len(registries))
+	for i := range registries {
+		items[i] = registries[i]
+	}
+
+	printResult(&output.Result{
+		Object: &struct {
+			Registries []*client.Registry `json:"registries"`
+		}{registries},
+		Items: items,
+		Columns: []output.Column{
+			{Header: "Registry", Value: func(item interface{}) string {
+				return item.(*client.Registry).Name
+			}},
+		},
+		Name: func(item interface{}) string {
+			return item.(*client.Registry).Name
+		},
+	}, fmt.Sprintf("No registries exist under '%s'", args[0]))
+}, namespaceArgs(1))
+
+//secretsCmd
+var secretsCmd = generateCmd("secrets", "List, create and delete secrets from the provided namespace", "", nil, nil)
+
+var createSecretCmd = generateCmd("create [NAMESPACE] KEY VALUE", "Creates a new secret on the provided namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 3)
+	err := api.CreateSecret(cmd.Context(), args[0], args[1], []byte(args[2]))
+	if err != nil {
+		fail(err)
 	}
-	table.Render()
-}, cobra.ExactArgs(1))
+	logger.Printf("Successfully created secret '%s'.", args[1])
+}, namespaceArgs(3))
+
+var removeSecretCmd = generateCmd("delete [NAMESPACE] KEY", "Deletes a secret from the provided namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 2)
+	err := api.DeleteSecret(cmd.Context(), args[0], args[1])
+	if err != nil {
+		fail(err)
+	}
+	logger.Printf("Successfully removed secret '%s'.", args[1])
+}, namespaceArgs(2))
+
+var listSecretsCmd = generateCmd("list [NAMESPACE]", "Returns a list of secrets for the provided namespace", "", func(cmd *cobra.Command, args []string) {
+	args = withNamespace(args, 1)
+	secrets, err := api.ListSecrets(cmd.Context(), args[0])
+	if err != nil {
+		fail(err)
+	}
+
+	items := make([]interface{}, len(secrets))
+	for i := range secrets {
+		items[i] = secrets[i]
+	}
+
+	printResult(&output.Result{
+		Object: &struct {
+			Secrets []*client.Secret `json:"secrets"`
+		}{secrets},
+		Items: items,
+		Columns: []output.Column{
+			{Header: "Secret", Value: func(item interface{}) string {
+				return item.(*client.Secret).Name
+			}},
+		},
+		Name: func(item interface{}) string {
+			return item.(*client.Secret).Name
+		},
+	}, fmt.Sprintf("No secrets exist under '%s'", args[0]))
+}, namespaceArgs(1))
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
@@ -385,11 +859,16 @@ func Execute() {
 	workflowCmd.AddCommand(workflowGetCmd)
 	workflowCmd.AddCommand(workflowExecuteCmd)
 	workflowCmd.AddCommand(workflowToggleCmd)
+	workflowCmd.AddCommand(workflowDiffCmd)
+	workflowCmd.AddCommand(workflowValidateCmd)
+	workflowCmd.AddCommand(workflowGraphCmd)
 
 	// Workflow instance commands
 	instanceCmd.AddCommand(instanceGetCmd)
 	instanceCmd.AddCommand(instanceListCmd)
 	instanceCmd.AddCommand(instanceLogsCmd)
+	instanceCmd.AddCommand(instanceCancelCmd)
+	instanceCmd.AddCommand(instanceRerunCmd)
 
 	// Secrets
 	secretsCmd.AddCommand(createSecretCmd)
@@ -401,22 +880,117 @@ func Execute() {
 	registriesCmd.AddCommand(removeRegistryCmd)
 	registriesCmd.AddCommand(listRegistriesCmd)
 
+	// Config
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+	configCmd.AddCommand(configSetCmd)
+
 	// Root Commands
 	rootCmd.AddCommand(namespaceCmd)
 	rootCmd.AddCommand(workflowCmd)
 	rootCmd.AddCommand(instanceCmd)
 	rootCmd.AddCommand(secretsCmd)
 	rootCmd.AddCommand(registriesCmd)
-
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(applyCmd)
+
+	// cancel in-flight requests on Ctrl-C, then stop catching signals so
+	// that a second Ctrl-C kills the process even while it is blocked on
+	// something that does not watch the context, such as a prompt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// errors are printed here rather than by cobra so that they are only
+	// printed once
+	rootCmd.SilenceErrors = true
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+
+		var se *setupError
+		if errors.As(err, &se) {
+			os.Exit(exitCode(err))
+		}
+		os.Exit(exitUsage)
 	}
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&flagGRPC, "grpc", "", "", "ip and port for connection GRPC default is 127.0.0.1:6666")
+	rootCmd.PersistentFlags().StringVarP(&flagGRPC, "grpc", "", "", "ip and port of the direktiv gRPC server, overrides the profile and DIREKCLI_ADDRESS")
+	rootCmd.PersistentFlags().StringVarP(&flagProfile, "profile", "", "", "name of the config profile to use")
+	rootCmd.PersistentFlags().BoolVarP(&flagTLS, "tls", "", false, "connect using TLS")
+	rootCmd.PersistentFlags().StringVarP(&flagCACert, "ca-cert", "", "", "filepath to the CA certificate used to verify the server, implies --tls")
+	rootCmd.PersistentFlags().StringVarP(&flagClientCert, "client-cert", "", "", "filepath to the client certificate for mutual TLS, implies --tls")
+	rootCmd.PersistentFlags().StringVarP(&flagClientKey, "client-key", "", "", "filepath to the client key for mutual TLS")
+	rootCmd.PersistentFlags().BoolVarP(&flagInsecureSkipVerify, "insecure-skip-verify", "", false, "do not verify the server certificate, implies --tls")
+	rootCmd.PersistentFlags().StringVarP(&flagToken, "token", "", "", "token sent with every request, requires TLS; a token from the profile or DIREKCLI_TOKEN is not sent without TLS")
+	rootCmd.PersistentFlags().StringVarP(&flagTokenFile, "token-file", "", "", "filepath to a file containing the token")
+	rootCmd.PersistentFlags().StringVarP(&flagTokenHeader, "token-header", "", "", "metadata key the token is sent in, e.g. x-api-key default is authorization")
+	rootCmd.PersistentFlags().StringVarP(&flagTokenScheme, "token-scheme", "", "", "prefix of the token, default is Bearer in the authorization header and none in others")
+	rootCmd.PersistentFlags().StringVarP(&flagTimeout, "timeout", "", "", "deadline for each request, e.g. 30s, 0 disables it default is 3s")
+	rootCmd.PersistentFlags().IntVarP(&flagRetries, "retries", "", -1, "number of times read requests are retried on transient errors, 0 disables it default is 3")
+	rootCmd.PersistentFlags().StringVarP(&flagRetryBackoff, "retry-backoff", "", "", "initial wait between retries, doubled for every retry default is 100ms")
+	rootCmd.PersistentFlags().StringVarP(&flagRetryMaxBackoff, "retry-max-backoff", "", "", "longest wait between retries default is 2s")
+	rootCmd.PersistentFlags().StringVarP(&flagRetryTimeout, "retry-timeout", "", "", "deadline for each attempt within --timeout, so that slow attempts are retried, 0 disables it default is 0")
+	rootCmd.PersistentFlags().StringVarP(&flagRetryCodes, "retry-codes", "", "", "comma separated gRPC status codes that are retried default is Unavailable,DeadlineExceeded")
+	rootCmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "", "output format, one of: "+strings.Join(output.Formats, ", ")+"; templates and custom columns refer to fields by their JSON names, e.g. go-template={{.id}}")
+	rootCmd.PersistentFlags().StringVarP(&flagConfig, "config", "", "", "path to the config file default is ~/.config/direkcli/config.yaml")
 
 	// workflowCmd add flag for the namespace
-	workflowExecuteCmd.PersistentFlags().StringVarP(&flagInputFile, "input", "", "", "filepath to json input")
+	workflowExecuteCmd.PersistentFlags().StringVarP(&flagInputFile, "input", "", "", "filepath to json input, '-' to read from stdin")
+	workflowExecuteCmd.Flags().StringVarP(&flagInputJSON, "input-json", "", "", "json input")
+	workflowExecuteCmd.Flags().StringArrayVarP(&flagSet, "set", "", nil, "set a field of the input to a string, as key=value (repeatable)")
+	workflowExecuteCmd.Flags().StringArrayVarP(&flagSetFile, "set-file", "", nil, "set a field of the input to the contents of a file, as JSON if it is valid JSON, as key=@path (repeatable)")
+	workflowExecuteCmd.Flags().BoolVarP(&flagWait, "wait", "w", false, "wait for the instance to finish and print its output")
+	workflowExecuteCmd.Flags().BoolVarP(&flagWaitLogs, "logs", "", false, "print the logs of the instance to stderr while waiting, implies --wait")
+	workflowExecuteCmd.Flags().DurationVarP(&flagInterval, "interval", "", time.Second, "how often to poll the instance while waiting")
+
+	instanceGetCmd.Flags().BoolVarP(&flagRaw, "raw", "", false, "print input and output exactly as stored, without indenting them")
+
+	instanceRerunCmd.Flags().StringVarP(&flagInputOverride, "input-override", "", "", "filepath to json input to use instead of the original, '-' to read from stdin")
+	instanceRerunCmd.Flags().BoolVarP(&flagWait, "wait", "w", false, "wait for the instance to finish and print its output")
+	instanceRerunCmd.Flags().BoolVarP(&flagWaitLogs, "logs", "", false, "print the logs of the instance to stderr while waiting, implies --wait")
+	instanceRerunCmd.Flags().DurationVarP(&flagInterval, "interval", "", time.Second, "how often to poll the instance while waiting")
+
+	workflowGraphCmd.Flags().StringVarP(&flagGraphFile, "filename", "f", "", "read the workflow from a local file instead of the server")
+	workflowGraphCmd.Flags().StringVarP(&flagGraphFormat, "format", "", definition.GraphASCII, "graph format, one of: "+strings.Join(definition.GraphFormats, ", "))
+
+	workflowDiffCmd.Flags().StringArrayVarP(&flagDiffFiles, "filename", "f", nil, "workflow file, or directory of workflow files (repeatable)")
+
+	applyCmd.Flags().StringVarP(&flagApplyNamespace, "namespace", "n", "", "namespace to apply the workflows to, defaults to the namespace of the profile")
+	applyCmd.Flags().StringArrayVarP(&flagApplyFiles, "filename", "f", nil, "workflow file, or directory of workflow files (repeatable)")
+	applyCmd.Flags().BoolVarP(&flagPrune, "prune", "", false, "delete workflows in the namespace that have no file")
+	applyCmd.Flags().BoolVarP(&flagDryRun, "dry-run", "", false, "show what would change without changing anything")
+	applyCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "do not ask for confirmation before pruning, required if stdin is not a terminal")
+
+	for _, c := range []*cobra.Command{instanceListCmd, workflowListCmd} {
+		c.Flags().BoolVarP(&flagWatch, "watch", "w", false, "keep listing, printing only what is new, has changed or was deleted")
+		c.Flags().DurationVarP(&flagWatchInterval, "interval", "", 2*time.Second, "how often to refresh with --watch")
+	}
+
+	instanceListCmd.Flags().StringSliceVarP(&flagListStatus, "status", "", nil, "only list instances with these statuses, one or more of: "+strings.Join(client.Statuses, ", "))
+	instanceListCmd.Flags().StringVarP(&flagListWorkflow, "workflow", "", "", "only list instances of this workflow")
+	instanceListCmd.Flags().StringVarP(&flagListSince, "since", "", "", "only list instances that began at or after this time")
+	instanceListCmd.Flags().StringVarP(&flagListUntil, "until", "", "", "only list instances that began before this time")
+	instanceListCmd.Flags().StringVarP(&flagListSort, "sort", "", "", "sort by one of: "+strings.Join(instanceSortKeys(), ", ")+", prefixed with '-' for descending order")
+	instanceListCmd.Flags().IntVarP(&flagListOffset, "offset", "", 0, "number of instances to skip")
+	instanceListCmd.Flags().IntVarP(&flagListLimit, "limit", "", 0, "maximum number of instances to list, 0 for no limit")
+
+	instanceCancelCmd.Flags().StringVarP(&flagAllRunning, "all-running", "", "", "cancel every running instance in this namespace")
+	instanceCancelCmd.Flags().StringVarP(&flagCancelWorkflow, "workflow", "", "", "only cancel instances of this workflow, with --all-running")
+	instanceCancelCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "do not ask for confirmation, required if stdin is not a terminal")
+
+	instanceLogsCmd.Flags().BoolVarP(&flagFollow, "follow", "f", false, "keep printing new logs until the instance has finished")
+	instanceLogsCmd.Flags().DurationVarP(&flagInterval, "interval", "", time.Second, "how often to poll for new logs with --follow")
+	instanceLogsCmd.Flags().IntVarP(&flagLogsOffset, "offset", "", 0, "index of the first log entry to show")
+	instanceLogsCmd.Flags().IntVarP(&flagLogsLimit, "limit", "", 10000, "maximum number of log entries to show")
+	instanceLogsCmd.Flags().IntVarP(&flagLogsTail, "tail", "", 0, "only show the last N log entries")
+	instanceLogsCmd.Flags().BoolVarP(&flagLogsAll, "all", "", false, "show every log entry, ignoring --limit")
+	instanceLogsCmd.Flags().StringVarP(&flagLogsFormat, "format", "", output.LogFormatText, "log format, one of: "+strings.Join(output.LogFormats, ", "))
+	instanceLogsCmd.Flags().BoolVarP(&flagLogsTimestamps, "timestamps", "", false, "prefix each line with its timestamp in the text format")
 }
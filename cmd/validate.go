@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cobra "github.com/spf13/cobra"
+	"github.com/vorteil/direkcli/pkg/definition"
+	log "github.com/vorteil/direkcli/pkg/log"
+)
+
+// offlinePreRunE replaces the root PersistentPreRunE for commands that work
+// only with local files, so that they neither connect nor read the config.
+func offlinePreRunE(cmd *cobra.Command, args []string) error {
+	logger = log.GetLogger()
+	return nil
+}
+
+// workflowValidateCmd
+var workflowValidateCmd = &cobra.Command{
+	Use:   "validate FILE...",
+	Short: "Checks workflow files for errors without sending them to the server",
+	Long: `Each file is checked with direktiv's own workflow model, as the server
+would check it, and for invalid jq expressions. States that cannot be
+reached and functions that are never called are reported as warnings.
+Problems are printed as FILE:LINE: SEVERITY: MESSAGE, and the command
+exits with code 2 if any file has errors.`,
+	Args:              cobra.MinimumNArgs(1),
+	PersistentPreRunE: offlinePreRunE,
+	Run: func(cmd *cobra.Command, args []string) {
+		failed := false
+
+		for _, path := range args {
+			problems, err := validateFile(path)
+			if err != nil {
+				fmt.Printf("%s: error: %v\n", path, err)
+				failed = true
+				continue
+			}
+
+			for _, p := range problems {
+				fmt.Printf("%s:%d: %s: %s\n", path, p.Line, p.Severity, p.Message)
+			}
+			failed = failed || definition.HasErrors(problems)
+		}
+
+		if failed {
+			os.Exit(exitUsage)
+		}
+	},
+}
+
+// validateFile parses and validates a workflow file. A file that is not
+// valid YAML is reported as a problem on the line the parser stopped at.
+func validateFile(path string) ([]*definition.Problem, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	wf, err := definition.Parse(b)
+	var p *definition.Problem
+	if errors.As(err, &p) {
+		return []*definition.Problem{p}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return definition.Validate(wf), nil
+}
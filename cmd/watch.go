@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/vorteil/direkcli/client"
+	"github.com/vorteil/direkcli/pkg/output"
+)
+
+// watchResults calls list every --interval until interrupted, printing only
+// the items that are new or have changed since the previous call. Errors
+// from an unavailable server are reported and retried at the next interval.
+func watchResults(ctx context.Context, list func() (*output.Result, error)) {
+	w := output.NewWatcher(os.Stdout, printer)
+
+	for {
+		r, err := list()
+		if err == nil {
+			err = w.Update(r)
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if !errors.Is(err, client.ErrUnavailable) {
+				fail(err)
+			}
+			logger.Warnf("%v", err)
+		}
+
+		timer := time.NewTimer(flagWatchInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
@@ -3,10 +3,16 @@ module github.com/vorteil/direkcli
 go 1.16
 
 require (
+	github.com/fatih/color v1.10.0
+	github.com/itchyny/gojq v0.12.1
+	github.com/mattn/go-isatty v0.0.12
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/sirupsen/logrus v1.8.0
 	github.com/sisatech/tablewriter v0.0.0-20161130023222-815eceb01ee6
 	github.com/spf13/cobra v1.1.3
 	github.com/vorteil/direktiv v0.0.0-20210219064752-4a1144b49d76
 	github.com/vorteil/vorteil v0.0.0-20210218050403-7d3e385fabb3
 	google.golang.org/grpc v1.35.0
+	google.golang.org/protobuf v1.25.0
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
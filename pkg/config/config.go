@@ -0,0 +1,434 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vorteil/direkcli/client"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is the name of the profile used when none has been selected.
+const DefaultProfile = "default"
+
+// DefaultAddress is the gRPC address used when no address has been configured.
+const DefaultAddress = "127.0.0.1:6666"
+
+// DefaultTimeout is the per-call deadline used when no timeout has been configured.
+const DefaultTimeout = client.DefaultTimeout
+
+// RedactedToken replaces stored tokens in the output of Config.Redacted.
+const RedactedToken = "REDACTED"
+
+// Environment variables that override the values of the active profile.
+const (
+	EnvConfig    = "DIREKCLI_CONFIG"
+	EnvProfile   = "DIREKCLI_PROFILE"
+	EnvAddress   = "DIREKCLI_ADDRESS"
+	EnvNamespace = "DIREKCLI_NAMESPACE"
+	EnvTimeout   = "DIREKCLI_TIMEOUT"
+	EnvToken     = "DIREKCLI_TOKEN"
+)
+
+// Keys lists the profile settings that can be changed with Profile.Set.
+var Keys = []string{"address", "namespace", "timeout", "tls", "ca-cert", "client-cert", "client-key", "insecure-skip-verify", "token", "token-file", "token-header", "token-scheme", "retries", "retry-backoff", "retry-max-backoff", "retry-timeout", "retry-codes"}
+
+// Profile holds the connection settings for a single direktiv server.
+type Profile struct {
+	Address   string `yaml:"address,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+	Timeout   string `yaml:"timeout,omitempty"`
+
+	TLS                bool   `yaml:"tls,omitempty"`
+	CACert             string `yaml:"ca-cert,omitempty"`
+	ClientCert         string `yaml:"client-cert,omitempty"`
+	ClientKey          string `yaml:"client-key,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify,omitempty"`
+
+	Token     string `yaml:"token,omitempty"`
+	TokenFile string `yaml:"token-file,omitempty"`
+	// TokenHeader and TokenScheme select how the token is sent, e.g.
+	// "x-api-key" without a scheme for API keys. They default to a bearer
+	// token in the authorization metadata.
+	TokenHeader string `yaml:"token-header,omitempty"`
+	TokenScheme string `yaml:"token-scheme,omitempty"`
+
+	// Retries is the number of times idempotent calls are retried, nil
+	// selects the default.
+	Retries         *int   `yaml:"retries,omitempty"`
+	RetryBackoff    string `yaml:"retry-backoff,omitempty"`
+	RetryMaxBackoff string `yaml:"retry-max-backoff,omitempty"`
+	RetryTimeout    string `yaml:"retry-timeout,omitempty"`
+	// RetryCodes is a comma separated list of the gRPC status codes that
+	// are retried, e.g. "Unavailable,DeadlineExceeded".
+	RetryCodes string `yaml:"retry-codes,omitempty"`
+}
+
+// Config is the content of the direkcli configuration file.
+type Config struct {
+	CurrentProfile string              `yaml:"current-profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles,omitempty"`
+
+	path string
+}
+
+// DefaultPath returns the location of the configuration file, which is
+// ~/.config/direkcli/config.yaml unless overridden by DIREKCLI_CONFIG.
+func DefaultPath() (string, error) {
+	if p := os.Getenv(EnvConfig); p != "" {
+		return p, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", "direkcli", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path. A missing file is not an error
+// and results in an empty configuration.
+func Load(path string) (*Config, error) {
+	c := &Config{
+		Profiles: make(map[string]*Profile),
+		path:     path,
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(b, c)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file '%s': %v", path, err)
+	}
+
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Profile)
+	}
+
+	return c, nil
+}
+
+// Save writes the configuration back to the file it was loaded from.
+func (c *Config) Save() error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path, b, 0600)
+}
+
+// Path returns the location of the configuration file.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// ProfileName returns the name of the profile that should be used, honouring
+// the DIREKCLI_PROFILE environment variable if name is empty.
+func (c *Config) ProfileName(name string) string {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	return name
+}
+
+// Redacted returns a copy of the config for printing, with every stored
+// token replaced by RedactedToken.
+func (c *Config) Redacted() *Config {
+	out := &Config{
+		CurrentProfile: c.CurrentProfile,
+		path:           c.path,
+	}
+
+	if c.Profiles != nil {
+		out.Profiles = make(map[string]*Profile, len(c.Profiles))
+		for name, p := range c.Profiles {
+			cp := *p
+			if cp.Token != "" {
+				cp.Token = RedactedToken
+			}
+			out.Profiles[name] = &cp
+		}
+	}
+
+	return out
+}
+
+// UseProfile makes the named profile the current profile.
+func (c *Config) UseProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("profile '%s' does not exist", name)
+	}
+	c.CurrentProfile = name
+	return nil
+}
+
+// Profile returns the named profile, creating it if it does not exist.
+func (c *Config) Profile(name string) *Profile {
+	p, ok := c.Profiles[name]
+	if !ok {
+		p = new(Profile)
+		c.Profiles[name] = p
+	}
+	return p
+}
+
+// Resolve returns a copy of the named profile with environment variable
+// overrides and defaults applied. Unlike Profile it does not modify the
+// configuration if the profile does not exist, unless it was explicitly
+// requested.
+func (c *Config) Resolve(name string) (*Profile, error) {
+	explicit := name != "" || os.Getenv(EnvProfile) != "" || c.CurrentProfile != ""
+	name = c.ProfileName(name)
+
+	p := new(Profile)
+	if stored, ok := c.Profiles[name]; ok {
+		*p = *stored
+	} else if explicit && name != DefaultProfile {
+		return nil, fmt.Errorf("profile '%s' does not exist", name)
+	}
+
+	if v := os.Getenv(EnvAddress); v != "" {
+		p.Address = v
+	}
+	if v := os.Getenv(EnvNamespace); v != "" {
+		p.Namespace = v
+	}
+	if v := os.Getenv(EnvTimeout); v != "" {
+		p.Timeout = v
+	}
+	if v := os.Getenv(EnvToken); v != "" {
+		p.Token = v
+		p.TokenFile = ""
+	}
+
+	if p.Address == "" {
+		p.Address = DefaultAddress
+	}
+
+	if _, err := p.GetTimeout(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// GetTimeout returns the profile's timeout as a duration, or DefaultTimeout
+// if none is set.
+func (p *Profile) GetTimeout() (time.Duration, error) {
+	if p.Timeout == "" {
+		return DefaultTimeout, nil
+	}
+
+	d, err := time.ParseDuration(p.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout '%s': %v", p.Timeout, err)
+	}
+
+	return d, nil
+}
+
+// GetToken returns the token used to authenticate calls, reading it from
+// TokenFile if no token is set directly. An empty token disables
+// authentication.
+func (p *Profile) GetToken() (string, error) {
+	if p.Token != "" || p.TokenFile == "" {
+		return p.Token, nil
+	}
+
+	b, err := ioutil.ReadFile(p.TokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// UsesTLS reports whether connections made with the profile use TLS, and so
+// whether its token can be sent.
+func (p *Profile) UsesTLS() bool {
+	c := &client.Config{
+		TLS:                p.TLS,
+		CACert:             p.CACert,
+		ClientCert:         p.ClientCert,
+		ClientKey:          p.ClientKey,
+		InsecureSkipVerify: p.InsecureSkipVerify,
+	}
+	return c.UsesTLS()
+}
+
+// ClientConfig converts the profile into the settings used to dial the
+// direktiv server.
+func (p *Profile) ClientConfig() (*client.Config, error) {
+	timeout, err := p.GetTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	retry := client.DefaultRetryPolicy()
+	if p.Retries != nil {
+		retry.MaxAttempts = *p.Retries + 1
+	}
+	if p.RetryBackoff != "" {
+		retry.InitialBackoff, err = time.ParseDuration(p.RetryBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry-backoff '%s': %v", p.RetryBackoff, err)
+		}
+	}
+	if p.RetryMaxBackoff != "" {
+		retry.MaxBackoff, err = time.ParseDuration(p.RetryMaxBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry-max-backoff '%s': %v", p.RetryMaxBackoff, err)
+		}
+	}
+	if p.RetryTimeout != "" {
+		retry.Timeout, err = time.ParseDuration(p.RetryTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry-timeout '%s': %v", p.RetryTimeout, err)
+		}
+	}
+	if p.RetryCodes != "" {
+		retry.Codes, err = parseCodes(p.RetryCodes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &client.Config{
+		Address:            p.Address,
+		Timeout:            timeout,
+		TLS:                p.TLS,
+		CACert:             p.CACert,
+		ClientCert:         p.ClientCert,
+		ClientKey:          p.ClientKey,
+		InsecureSkipVerify: p.InsecureSkipVerify,
+		Token:              token,
+		TokenHeader:        p.TokenHeader,
+		TokenScheme:        p.TokenScheme,
+		Retry:              retry,
+	}, nil
+}
+
+// Set changes a single setting of the profile identified by key.
+func (p *Profile) Set(key, value string) error {
+	switch key {
+	case "address":
+		p.Address = value
+	case "namespace":
+		p.Namespace = value
+	case "timeout":
+		if value != "" {
+			if _, err := time.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid timeout '%s': %v", value, err)
+			}
+		}
+		p.Timeout = value
+	case "tls", "insecure-skip-verify":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value '%s' for '%s', expected true or false", value, key)
+		}
+		if key == "tls" {
+			p.TLS = b
+		} else {
+			p.InsecureSkipVerify = b
+		}
+	case "ca-cert":
+		p.CACert = value
+	case "client-cert":
+		p.ClientCert = value
+	case "client-key":
+		p.ClientKey = value
+	case "token":
+		p.Token = value
+	case "token-file":
+		p.TokenFile = value
+	case "token-header":
+		p.TokenHeader = value
+	case "token-scheme":
+		p.TokenScheme = value
+	case "retries":
+		if value == "" {
+			p.Retries = nil
+			break
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid retries '%s', expected a number of at least 0", value)
+		}
+		p.Retries = &n
+	case "retry-backoff", "retry-max-backoff", "retry-timeout":
+		if value != "" {
+			if _, err := time.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid %s '%s': %v", key, value, err)
+			}
+		}
+		switch key {
+		case "retry-backoff":
+			p.RetryBackoff = value
+		case "retry-max-backoff":
+			p.RetryMaxBackoff = value
+		default:
+			p.RetryTimeout = value
+		}
+	case "retry-codes":
+		if value != "" {
+			if _, err := parseCodes(value); err != nil {
+				return err
+			}
+		}
+		p.RetryCodes = value
+	default:
+		return fmt.Errorf("unknown key '%s', expected one of: %s", key, strings.Join(Keys, ", "))
+	}
+	return nil
+}
+
+// parseCodes parses a comma separated list of gRPC status code names, which
+// are matched ignoring case.
+func parseCodes(s string) ([]codes.Code, error) {
+	var out []codes.Code
+
+next:
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		for c := codes.OK; c <= codes.Unauthenticated; c++ {
+			if strings.EqualFold(c.String(), name) {
+				out = append(out, c)
+				continue next
+			}
+		}
+		return nil, fmt.Errorf("invalid retry-codes '%s', unknown status code '%s'", s, name)
+	}
+
+	return out, nil
+}
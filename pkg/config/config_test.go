@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRedacted(t *testing.T) {
+	c := &Config{
+		CurrentProfile: "prod",
+		Profiles: map[string]*Profile{
+			"prod":  {Address: "prod:6666", Token: "secret"},
+			"local": {Address: "127.0.0.1:6666"},
+		},
+	}
+
+	b, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "secret") {
+		t.Fatalf("token was not redacted:\n%s", b)
+	}
+	if !strings.Contains(string(b), "token: "+RedactedToken) {
+		t.Fatalf("expected a redacted token:\n%s", b)
+	}
+	if strings.Count(string(b), "token:") != 1 {
+		t.Fatalf("profiles without a token should not get one:\n%s", b)
+	}
+
+	if c.Profiles["prod"].Token != "secret" {
+		t.Fatal("Redacted modified the original config")
+	}
+}
+
+// setenv sets an environment variable for the duration of the test.
+func setenv(t *testing.T, key, value string) {
+	t.Helper()
+
+	prev, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestResolve(t *testing.T) {
+	for _, key := range []string{EnvProfile, EnvAddress, EnvNamespace, EnvTimeout, EnvToken} {
+		setenv(t, key, "")
+	}
+
+	newConfig := func() *Config {
+		return &Config{
+			CurrentProfile: "prod",
+			Profiles: map[string]*Profile{
+				"prod":  {Address: "prod:6666", Namespace: "ops", TokenFile: "token.txt"},
+				"local": {Namespace: "dev", Timeout: "5s"},
+				"bad":   {Timeout: "soon"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		noCurrent bool
+		profile   string
+		env       map[string]string
+		want      Profile
+		err       bool
+	}{
+		{
+			name: "current profile",
+			want: Profile{Address: "prod:6666", Namespace: "ops", TokenFile: "token.txt"},
+		},
+		{
+			name:    "named profile gets default address",
+			profile: "local",
+			want:    Profile{Address: DefaultAddress, Namespace: "dev", Timeout: "5s"},
+		},
+		{
+			name: "profile from environment",
+			env:  map[string]string{EnvProfile: "local"},
+			want: Profile{Address: DefaultAddress, Namespace: "dev", Timeout: "5s"},
+		},
+		{
+			name:    "flag beats environment profile",
+			profile: "prod",
+			env:     map[string]string{EnvProfile: "local"},
+			want:    Profile{Address: "prod:6666", Namespace: "ops", TokenFile: "token.txt"},
+		},
+		{
+			name: "environment overrides",
+			env: map[string]string{
+				EnvAddress:   "env:6666",
+				EnvNamespace: "env",
+				EnvTimeout:   "1m",
+				EnvToken:     "secret",
+			},
+			want: Profile{Address: "env:6666", Namespace: "env", Timeout: "1m", Token: "secret"},
+		},
+		{
+			name:      "missing default profile",
+			noCurrent: true,
+			want:      Profile{Address: DefaultAddress},
+		},
+		{
+			name:    "missing profile",
+			profile: "staging",
+			err:     true,
+		},
+		{
+			name:    "invalid timeout",
+			profile: "bad",
+			err:     true,
+		},
+		{
+			name: "invalid timeout from environment",
+			env:  map[string]string{EnvTimeout: "soon"},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				setenv(t, k, v)
+			}
+
+			c := newConfig()
+			if tt.noCurrent {
+				c.CurrentProfile = ""
+			}
+
+			p, err := c.Resolve(tt.profile)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(*p, tt.want) {
+				t.Fatalf("got %+v, want %+v", *p, tt.want)
+			}
+
+			if !reflect.DeepEqual(c.Profiles, newConfig().Profiles) {
+				t.Fatalf("Resolve modified the profiles")
+			}
+		})
+	}
+}
+
+func TestClientConfigRetry(t *testing.T) {
+	retries := 1
+	p := &Profile{
+		Retries:         &retries,
+		RetryBackoff:    "10ms",
+		RetryMaxBackoff: "1s",
+		RetryTimeout:    "500ms",
+		RetryCodes:      "unavailable, ResourceExhausted",
+	}
+
+	c, err := p.ClientConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := c.Retry
+	if r.MaxAttempts != 2 || r.InitialBackoff != time.Millisecond*10 || r.MaxBackoff != time.Second || r.Timeout != time.Millisecond*500 {
+		t.Fatalf("got %+v", r)
+	}
+	if want := []codes.Code{codes.Unavailable, codes.ResourceExhausted}; !reflect.DeepEqual(r.Codes, want) {
+		t.Fatalf("got codes %v, want %v", r.Codes, want)
+	}
+
+	if err = p.Set("retry-codes", "Unavailable,Sometimes"); err == nil {
+		t.Fatal("expected an error for an unknown status code")
+	}
+}
@@ -0,0 +1,337 @@
+// Package definition parses direktiv workflow definitions offline, keeping
+// the line of every element so that problems can be reported against the
+// YAML they were found in. The definition itself is checked by direktiv's
+// own workflow model, see Validate.
+package definition
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of transition between states.
+const (
+	TransitionNext      = "transition"
+	TransitionCondition = "condition"
+	TransitionDefault   = "default"
+	TransitionEvent     = "event"
+	TransitionCatch     = "catch"
+)
+
+// Workflow is the part of a workflow definition needed to lint and draw its
+// state machine.
+type Workflow struct {
+	ID        string
+	Functions []*Function
+	States    []*State
+	Start     *Start
+
+	source []byte
+}
+
+// Function is a function declared by a workflow.
+type Function struct {
+	ID    string
+	Image string
+	Line  int
+}
+
+// Start is the start definition of a workflow, describing how it is
+// triggered and which state it begins in.
+type Start struct {
+	Type string
+	// State is the ID of the first state, if it is not the first in the
+	// list of states.
+	State string
+	Cron  string
+	// Events are the types of the events that trigger the workflow.
+	Events []string
+	Line   int
+}
+
+// State is a single state of a workflow.
+type State struct {
+	ID          string
+	Type        string
+	Line        int
+	Transitions []*Transition
+	// Functions are the functions called by the actions of the state.
+	Functions []*Ref
+	// Expressions are the jq expressions used by the state.
+	Expressions []*Ref
+	// Events are the types of the events the state consumes or generates.
+	Events []string
+}
+
+// Transition is an edge from one state to another.
+type Transition struct {
+	To   string
+	Kind string
+	// Label describes when the transition is taken, e.g. the condition of
+	// a switch or the error code of a catch.
+	Label string
+	Line  int
+}
+
+// Ref is a string found in a definition, e.g. a function name or a jq
+// expression, with its position.
+type Ref struct {
+	Field string
+	Value string
+	Line  int
+}
+
+// Parse reads a workflow definition. It only fails if the YAML itself is
+// invalid or is not a mapping; use Validate to check the workflow. States
+// are read without regard to their type, so that the transitions, function
+// calls and jq expressions of any state direktiv supports are found. The
+// error returned is a *Problem, so that it can be reported by line.
+func Parse(data []byte) (*Workflow, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, yamlProblem(err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, &Problem{Line: 1, Severity: SeverityError, Message: "empty workflow definition"}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &Problem{Line: root.Line, Severity: SeverityError, Message: "workflow definition is not a mapping"}
+	}
+
+	wf := &Workflow{
+		ID:     scalar(field(root, "id")),
+		source: data,
+	}
+
+	for _, n := range items(field(root, "functions")) {
+		wf.Functions = append(wf.Functions, &Function{
+			ID:    scalar(field(n, "id")),
+			Image: scalar(field(n, "image")),
+			Line:  n.Line,
+		})
+	}
+
+	if n := field(root, "start"); n != nil {
+		wf.Start = parseStart(n)
+	}
+
+	for _, n := range items(field(root, "states")) {
+		wf.States = append(wf.States, parseState(n))
+	}
+
+	return wf, nil
+}
+
+// StartState returns the ID of the state the workflow begins in, or an
+// empty string if it has no states.
+func (wf *Workflow) StartState() string {
+	if wf.Start != nil && wf.Start.State != "" {
+		return wf.Start.State
+	}
+	if len(wf.States) > 0 {
+		return wf.States[0].ID
+	}
+	return ""
+}
+
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// yamlProblem converts an error from the YAML parser, such as "yaml: line 3:
+// mapping values are not allowed in this context", into a problem on the
+// line it names, or on the first line if it names none.
+func yamlProblem(err error) *Problem {
+	msg := err.Error()
+
+	line := 1
+	if m := yamlLine.FindStringSubmatch(msg); m != nil {
+		line, _ = strconv.Atoi(m[1])
+		msg = msg[len(m[0]):]
+	}
+
+	return &Problem{
+		Line:     line,
+		Severity: SeverityError,
+		Message:  strings.TrimPrefix(msg, "yaml: "),
+	}
+}
+
+func parseStart(n *yaml.Node) *Start {
+	s := &Start{
+		Type:  scalar(field(n, "type")),
+		State: scalar(field(n, "state")),
+		Cron:  scalar(field(n, "cron")),
+		Line:  n.Line,
+	}
+
+	if ev := field(n, "event"); ev != nil {
+		s.Events = append(s.Events, scalar(field(ev, "type")))
+	}
+	for _, ev := range items(field(n, "events")) {
+		s.Events = append(s.Events, scalar(field(ev, "type")))
+	}
+
+	return s
+}
+
+// Fields whose string values are jq expressions.
+var expressionFields = map[string]bool{
+	"array":            true,
+	"condition":        true,
+	"data":             true,
+	"defaultTransform": true,
+	"input":            true,
+	"transform":        true,
+}
+
+func parseState(n *yaml.Node) *State {
+	s := &State{
+		ID:   scalar(field(n, "id")),
+		Type: scalar(field(n, "type")),
+		Line: n.Line,
+	}
+
+	s.walk(n, TransitionNext, "")
+
+	return s
+}
+
+// walk collects the transitions, function calls, jq expressions and events
+// found in the mapping n and anything nested in it. Transitions directly in
+// n are of the given kind, those in the items of catch, conditions and
+// events take their kind and label from the item.
+func (s *State) walk(n *yaml.Node, kind, label string) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, v := n.Content[i].Value, n.Content[i+1]
+
+		switch key {
+		case "transition":
+			s.addTransition(v, kind, label)
+
+		case "defaultTransition":
+			s.addTransition(v, TransitionDefault, "")
+
+		case "function":
+			s.addFunction(v)
+
+		case "schema":
+			// JSON schemas may use any field name
+
+		case "catch":
+			for _, c := range items(v) {
+				s.walk(c, TransitionCatch, scalar(field(c, "error")))
+			}
+
+		case "conditions":
+			for _, c := range items(v) {
+				s.walk(c, TransitionCondition, scalar(field(c, "condition")))
+			}
+
+		case "events":
+			for _, e := range items(v) {
+				s.addEvent(e)
+				s.walk(e, TransitionEvent, scalar(field(field(e, "event"), "type")))
+			}
+
+		case "event":
+			s.addEvent(v)
+			s.walk(v, kind, label)
+
+		default:
+			if expressionFields[key] {
+				s.addExpression(key, v)
+			}
+
+			s.walk(v, kind, label)
+			for _, x := range items(v) {
+				s.walk(x, kind, label)
+			}
+		}
+	}
+}
+
+func (s *State) addTransition(n *yaml.Node, kind, label string) {
+	if n == nil {
+		return
+	}
+
+	s.Transitions = append(s.Transitions, &Transition{
+		To:    scalar(n),
+		Kind:  kind,
+		Label: label,
+		Line:  n.Line,
+	})
+}
+
+// addExpression records the value of the named field as a jq expression if
+// it is a string. Other values, e.g. objects, are used by direktiv as they
+// are.
+func (s *State) addExpression(name string, x *yaml.Node) {
+	if x.Kind != yaml.ScalarNode || x.Tag != "!!str" {
+		return
+	}
+
+	s.Expressions = append(s.Expressions, &Ref{
+		Field: name,
+		Value: x.Value,
+		Line:  x.Line,
+	})
+}
+
+func (s *State) addFunction(n *yaml.Node) {
+	if n.Kind != yaml.ScalarNode {
+		return
+	}
+
+	s.Functions = append(s.Functions, &Ref{
+		Field: "function",
+		Value: n.Value,
+		Line:  n.Line,
+	})
+}
+
+func (s *State) addEvent(n *yaml.Node) {
+	if t := scalar(field(n, "type")); t != "" {
+		s.Events = append(s.Events, t)
+	}
+}
+
+// field returns the value of key in the mapping n, or nil.
+func field(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// items returns the elements of the sequence n, or nil.
+func items(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// scalar returns the value of n if it is a scalar, or an empty string.
+func scalar(n *yaml.Node) string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
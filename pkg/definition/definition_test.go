@@ -0,0 +1,168 @@
+package definition
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testWorkflow = `id: x
+functions:
+- id: fn
+  image: vorteil/request
+states:
+- id: check
+  type: switch
+  conditions:
+  - condition: '.ok'
+    transition: call
+  defaultTransition: wait
+- id: call
+  type: parallel
+  actions:
+  - function: fn
+    input: '.a'
+  catch:
+  - error: '*'
+    transition: wait
+- id: wait
+  type: eventsXor
+  events:
+  - event:
+      type: approved
+    transition: send
+- id: send
+  type: generateEvent
+  event:
+    type: done
+    data: '.result'
+  transform:
+    result: 1
+- id: check-input
+  type: validate
+  schema:
+    type: object
+    properties:
+      transition:
+        type: string
+`
+
+func TestParse(t *testing.T) {
+	wf, err := Parse([]byte(testWorkflow))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type transition struct {
+		To, Kind, Label string
+		Line            int
+	}
+
+	tests := []struct {
+		id          string
+		transitions []transition
+		functions   []string
+		expressions []string
+		events      []string
+	}{
+		{
+			id: "check",
+			transitions: []transition{
+				{"call", TransitionCondition, ".ok", 10},
+				{"wait", TransitionDefault, "", 11},
+			},
+			expressions: []string{"condition=.ok"},
+		},
+		{
+			id: "call",
+			transitions: []transition{
+				{"wait", TransitionCatch, "*", 19},
+			},
+			functions:   []string{"fn"},
+			expressions: []string{"input=.a"},
+		},
+		{
+			id: "wait",
+			transitions: []transition{
+				{"send", TransitionEvent, "approved", 25},
+			},
+			events: []string{"approved"},
+		},
+		{
+			id:          "send",
+			expressions: []string{"data=.result"},
+			events:      []string{"done"},
+		},
+		{
+			id: "check-input",
+		},
+	}
+
+	if len(wf.States) != len(tests) {
+		t.Fatalf("got %d states, want %d", len(wf.States), len(tests))
+	}
+
+	for i, tt := range tests {
+		s := wf.States[i]
+		t.Run(tt.id, func(t *testing.T) {
+			if s.ID != tt.id {
+				t.Fatalf("got state '%s', want '%s'", s.ID, tt.id)
+			}
+
+			var transitions []transition
+			for _, x := range s.Transitions {
+				transitions = append(transitions, transition{x.To, x.Kind, x.Label, x.Line})
+			}
+			if !reflect.DeepEqual(transitions, tt.transitions) {
+				t.Errorf("got transitions %v, want %v", transitions, tt.transitions)
+			}
+
+			var functions []string
+			for _, fn := range s.Functions {
+				functions = append(functions, fn.Value)
+			}
+			if !reflect.DeepEqual(functions, tt.functions) {
+				t.Errorf("got functions %v, want %v", functions, tt.functions)
+			}
+
+			var expressions []string
+			for _, x := range s.Expressions {
+				expressions = append(expressions, x.Field+"="+x.Value)
+			}
+			if !reflect.DeepEqual(expressions, tt.expressions) {
+				t.Errorf("got expressions %v, want %v", expressions, tt.expressions)
+			}
+
+			if !reflect.DeepEqual(s.Events, tt.events) {
+				t.Errorf("got events %v, want %v", s.Events, tt.events)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		line    int
+		message string
+	}{
+		{name: "empty", yaml: "", line: 1, message: "empty workflow definition"},
+		{name: "not a mapping", yaml: "\n- a\n- b\n", line: 2, message: "workflow definition is not a mapping"},
+		{name: "invalid yaml", yaml: "id: x\nstates:\n\t- id: a\n", line: 3, message: "found character that cannot start any token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+
+			var p *Problem
+			if !errors.As(err, &p) {
+				t.Fatalf("got %v, want a *Problem", err)
+			}
+			if p.Line != tt.line || p.Severity != SeverityError || p.Message != tt.message {
+				t.Fatalf("got line %d: %s: %s, want line %d: error: %s", p.Line, p.Severity, p.Message, tt.line, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package definition
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Graph formats supported by WriteGraph.
+const (
+	GraphASCII   = "ascii"
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+)
+
+// GraphFormats lists the names accepted by WriteGraph.
+var GraphFormats = []string{GraphASCII, GraphDOT, GraphMermaid}
+
+// CheckGraphFormat returns an error if format is not one of GraphFormats. An
+// empty format selects GraphASCII.
+func CheckGraphFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+
+	for _, f := range GraphFormats {
+		if f == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown graph format '%s', expected one of: %s", format, strings.Join(GraphFormats, ", "))
+}
+
+// WriteGraph draws the state machine of a workflow in the named format:
+// Graphviz DOT, a Mermaid flowchart, or an outline for the terminal. Every
+// graph starts at a node describing how the workflow is triggered, and
+// states without a transition lead to an end node. Catches are drawn as
+// dashed edges. A workflow without a start state, e.g. one with no states,
+// cannot be drawn and is an error.
+func WriteGraph(w io.Writer, wf *Workflow, format string) error {
+	err := CheckGraphFormat(format)
+	if err != nil {
+		return err
+	}
+
+	if wf.StartState() == "" {
+		return fmt.Errorf("workflow has no start state, so there is no graph to draw")
+	}
+
+	g := &graphWriter{w: w, wf: wf}
+
+	switch format {
+	case "", GraphASCII:
+		g.ascii()
+	case GraphDOT:
+		g.dot()
+	case GraphMermaid:
+		g.mermaid()
+	}
+
+	return g.err
+}
+
+// trigger describes how the workflow is started.
+func (wf *Workflow) trigger() string {
+	if wf.Start == nil || wf.Start.Type == "" || wf.Start.Type == "default" {
+		return "start"
+	}
+
+	switch {
+	case wf.Start.Cron != "":
+		return fmt.Sprintf("start: %s %s", wf.Start.Type, wf.Start.Cron)
+	case len(wf.Start.Events) > 0:
+		return fmt.Sprintf("start: %s %s", wf.Start.Type, strings.Join(wf.Start.Events, ", "))
+	}
+	return "start: " + wf.Start.Type
+}
+
+// ends reports whether the workflow can finish in the state: a switch
+// without a default transition ends if no condition matches, and any other
+// state ends if it has no transition apart from catches.
+func (s *State) ends() bool {
+	for _, t := range s.Transitions {
+		switch t.Kind {
+		case TransitionNext, TransitionDefault, TransitionEvent:
+			return false
+		}
+	}
+	return true
+}
+
+// label describes a transition, or is empty for plain transitions.
+func (t *Transition) label() string {
+	switch t.Kind {
+	case TransitionCatch:
+		return "catch: " + t.Label
+	case TransitionDefault:
+		return "default"
+	case TransitionCondition, TransitionEvent:
+		return t.Label
+	}
+	return ""
+}
+
+type graphWriter struct {
+	w   io.Writer
+	wf  *Workflow
+	err error
+}
+
+func (g *graphWriter) printf(format string, args ...interface{}) {
+	if g.err == nil {
+		_, g.err = fmt.Fprintf(g.w, format, args...)
+	}
+}
+
+func (g *graphWriter) ascii() {
+	reachable := Reachable(g.wf)
+
+	g.printf("(%s)\n", g.wf.trigger())
+	g.printf("  └─▶ %s\n", g.wf.StartState())
+
+	for _, s := range g.wf.States {
+		g.printf("\n[%s] %s", s.ID, s.Type)
+		if len(s.Events) > 0 {
+			g.printf(" (%s)", strings.Join(s.Events, ", "))
+		}
+		if !reachable[s.ID] {
+			g.printf(" (unreachable)")
+		}
+		g.printf("\n")
+
+		edges := s.Transitions
+		end := s.ends()
+		for i, t := range edges {
+			branch := "├"
+			if i == len(edges)-1 && !end {
+				branch = "└"
+			}
+
+			arrow := "─▶"
+			if t.Kind == TransitionCatch {
+				arrow = "┄▶"
+			}
+
+			g.printf("  %s%s %s", branch, arrow, t.To)
+			if l := t.label(); l != "" {
+				g.printf("  (%s)", l)
+			}
+			g.printf("\n")
+		}
+
+		if end {
+			g.printf("  └─▶ (end)\n")
+		}
+	}
+}
+
+func (g *graphWriter) dot() {
+	g.printf("digraph %s {\n", dotQuote(g.wf.ID))
+	g.printf("  node [shape=box, style=rounded];\n")
+	g.printf("  __start [shape=circle, label=%s];\n", dotQuote(g.wf.trigger()))
+	g.printf("  __end [shape=doublecircle, label=\"end\"];\n")
+
+	for _, s := range g.wf.States {
+		lines := []string{dotEscape(s.ID), dotEscape(s.Type)}
+		if len(s.Events) > 0 {
+			lines = append(lines, dotEscape(strings.Join(s.Events, ", ")))
+		}
+		g.printf("  %s [label=\"%s\"];\n", dotQuote(s.ID), strings.Join(lines, `\n`))
+	}
+
+	g.printf("  __start -> %s;\n", dotQuote(g.wf.StartState()))
+
+	for _, s := range g.wf.States {
+		for _, t := range s.Transitions {
+			var attrs []string
+			if l := t.label(); l != "" {
+				attrs = append(attrs, "label="+dotQuote(l))
+			}
+			if t.Kind == TransitionCatch {
+				attrs = append(attrs, "style=dashed", "color=red")
+			}
+
+			g.printf("  %s -> %s", dotQuote(s.ID), dotQuote(t.To))
+			if len(attrs) > 0 {
+				g.printf(" [%s]", strings.Join(attrs, ", "))
+			}
+			g.printf(";\n")
+		}
+
+		if s.ends() {
+			g.printf("  %s -> __end;\n", dotQuote(s.ID))
+		}
+	}
+
+	g.printf("}\n")
+}
+
+// dotEscape escapes backslashes and quotes for use in a DOT string.
+func dotEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`)
+}
+
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+func (g *graphWriter) mermaid() {
+	// state IDs may contain characters mermaid does not allow in node IDs,
+	// so nodes are numbered and labelled with the state ID instead
+	nodes := make(map[string]string)
+	node := func(id string) string {
+		n, ok := nodes[id]
+		if !ok {
+			n = fmt.Sprintf("s%d", len(nodes))
+			nodes[id] = n
+		}
+		return n
+	}
+
+	g.printf("flowchart TD\n")
+	g.printf("  __start((%s))\n", mermaidQuote(g.wf.trigger()))
+	g.printf("  __end(((end)))\n")
+
+	for _, s := range g.wf.States {
+		label := s.ID + "<br/>" + s.Type
+		if len(s.Events) > 0 {
+			label += "<br/>" + strings.Join(s.Events, ", ")
+		}
+		g.printf("  %s(%s)\n", node(s.ID), mermaidQuote(label))
+	}
+
+	g.printf("  __start --> %s\n", node(g.wf.StartState()))
+
+	for _, s := range g.wf.States {
+		for _, t := range s.Transitions {
+			l := t.label()
+			switch {
+			case t.Kind == TransitionCatch:
+				g.printf("  %s -. %s .-> %s\n", node(s.ID), mermaidQuote(l), node(t.To))
+			case l != "":
+				g.printf("  %s -- %s --> %s\n", node(s.ID), mermaidQuote(l), node(t.To))
+			default:
+				g.printf("  %s --> %s\n", node(s.ID), node(t.To))
+			}
+		}
+
+		if s.ends() {
+			g.printf("  %s --> __end\n", node(s.ID))
+		}
+	}
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,60 @@
+package definition
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteGraphDOTEscapesLabels(t *testing.T) {
+	wf, err := Parse([]byte(`id: x
+states:
+- id: 'a\b"c'
+  type: noop
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteGraph(&buf, wf, GraphDOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `"a\\b\"c" [label="a\\b\"c\nnoop"];`
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("missing %s in:\n%s", want, buf.String())
+	}
+}
+
+func TestWriteGraphWithoutStartState(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{name: "no states", yaml: "id: x\n"},
+		{name: "empty states", yaml: "id: x\nstates: []\n"},
+		{name: "first state without id", yaml: "id: x\nstates:\n- type: noop\n"},
+	}
+
+	for _, tt := range tests {
+		for _, format := range GraphFormats {
+			t.Run(tt.name+"/"+format, func(t *testing.T) {
+				wf, err := Parse([]byte(tt.yaml))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var buf bytes.Buffer
+				err = WriteGraph(&buf, wf, format)
+				if err == nil {
+					t.Fatalf("expected an error, got:\n%s", buf.String())
+				}
+				if buf.Len() > 0 {
+					t.Fatalf("wrote a partial graph:\n%s", buf.String())
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package definition
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/itchyny/gojq"
+	"github.com/vorteil/direktiv/pkg/model"
+)
+
+// Severities of a problem.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is an issue found in a workflow definition.
+type Problem struct {
+	Line     int
+	Severity string
+	Message  string
+}
+
+// Error implements the error interface.
+func (p *Problem) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Severity, p.Message)
+}
+
+// Validate checks the states, transitions, function references and jq
+// expressions of a workflow, and then checks it with direktiv's own
+// workflow model, so that a definition is only accepted if the server would
+// accept it. States that cannot be reached from the start state and
+// functions that are never called are warnings, all other problems are
+// errors. Problems are ordered by line.
+func Validate(wf *Workflow) []*Problem {
+	v := new(validator)
+	v.validate(wf)
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		return v.problems[i].Line < v.problems[j].Line
+	})
+
+	return v.problems
+}
+
+// HasErrors reports whether any of the problems is an error.
+func HasErrors(problems []*Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type validator struct {
+	problems []*Problem
+}
+
+func (v *validator) errorf(line int, format string, args ...interface{}) {
+	v.problems = append(v.problems, &Problem{
+		Line:     line,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) warnf(line int, format string, args ...interface{}) {
+	v.problems = append(v.problems, &Problem{
+		Line:     line,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validate(wf *Workflow) {
+	if wf.ID == "" {
+		v.errorf(1, "workflow has no id")
+	}
+
+	functions := make(map[string]bool)
+	for _, fn := range wf.Functions {
+		switch {
+		case fn.ID == "":
+			v.errorf(fn.Line, "function has no id")
+		case functions[fn.ID]:
+			v.errorf(fn.Line, "function '%s' is defined more than once", fn.ID)
+		}
+		functions[fn.ID] = true
+	}
+
+	states := make(map[string]*State)
+	for _, s := range wf.States {
+		switch {
+		case s.ID == "":
+			v.errorf(s.Line, "state has no id")
+		case states[s.ID] != nil:
+			v.errorf(s.Line, "state '%s' is defined more than once", s.ID)
+		default:
+			states[s.ID] = s
+		}
+
+		if s.Type == "" {
+			v.errorf(s.Line, "state '%s' has no type", s.ID)
+		}
+	}
+
+	if len(wf.States) == 0 {
+		v.errorf(1, "workflow has no states, so it has no start state")
+	}
+
+	// without a start definition the first state is the start state, and a
+	// missing id has already been reported against it
+	start := wf.StartState()
+	if wf.Start != nil && len(wf.States) > 0 && states[start] == nil {
+		v.errorf(wf.Start.Line, "start state '%s' is not defined", start)
+	}
+
+	used := make(map[string]bool)
+	for _, s := range wf.States {
+		for _, t := range s.Transitions {
+			if t.To == "" {
+				v.errorf(t.Line, "state '%s' has an empty %s", s.ID, t.Kind)
+			} else if states[t.To] == nil {
+				v.errorf(t.Line, "state '%s' transitions to undefined state '%s'", s.ID, t.To)
+			}
+		}
+
+		for _, fn := range s.Functions {
+			used[fn.Value] = true
+			if !functions[fn.Value] {
+				v.errorf(fn.Line, "state '%s' calls undefined function '%s'", s.ID, fn.Value)
+			}
+		}
+
+		for _, x := range s.Expressions {
+			_, err := gojq.Parse(x.Value)
+			if err != nil {
+				v.errorf(x.Line, "state '%s' has an invalid jq expression in %s: %v", s.ID, x.Field, err)
+			}
+		}
+	}
+
+	// the model reports only the first error it finds and not where, so it
+	// is only reported if it found something the checks above did not,
+	// e.g. an unknown state type or a missing field
+	if !HasErrors(v.problems) {
+		var m model.Workflow
+		err := m.Load(wf.source)
+		if err != nil {
+			v.errorf(errorLine(wf, err.Error()), "%v", err)
+		}
+	}
+
+	for _, fn := range wf.Functions {
+		if fn.ID != "" && !used[fn.ID] {
+			v.warnf(fn.Line, "function '%s' is never called", fn.ID)
+			// a function defined twice is only reported at its first
+			// definition
+			used[fn.ID] = true
+		}
+	}
+
+	// every state is unreachable without a start state
+	if states[start] == nil {
+		return
+	}
+
+	reachable := Reachable(wf)
+	for _, s := range wf.States {
+		if s.ID != "" && !reachable[s.ID] {
+			v.warnf(s.Line, "state '%s' cannot be reached from the start state", s.ID)
+		}
+	}
+}
+
+var (
+	lineNumber = regexp.MustCompile(`line (\d+)`)
+	stateIndex = regexp.MustCompile(`states\[(\d+)\]`)
+	quoted     = regexp.MustCompile(`'([^']*)'|"([^"]*)"`)
+)
+
+// errorLine finds the line an error from the workflow model refers to. The
+// model does not report lines, so they are taken from a line number or
+// state index in the message, or from the first state or function it
+// names. Anything else is reported against the first line.
+func errorLine(wf *Workflow, msg string) int {
+	if m := lineNumber.FindStringSubmatch(msg); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+
+	if m := stateIndex.FindStringSubmatch(msg); m != nil {
+		i, _ := strconv.Atoi(m[1])
+		if i < len(wf.States) {
+			return wf.States[i].Line
+		}
+	}
+
+	for _, m := range quoted.FindAllStringSubmatch(msg, -1) {
+		name := m[1] + m[2]
+		if name == "" {
+			continue
+		}
+
+		for _, s := range wf.States {
+			if s.ID == name {
+				return s.Line
+			}
+		}
+		for _, fn := range wf.Functions {
+			if fn.ID == name {
+				return fn.Line
+			}
+		}
+	}
+
+	return 1
+}
+
+// Reachable returns the IDs of the states that can be reached from the start
+// state, including the start state itself.
+func Reachable(wf *Workflow) map[string]bool {
+	states := make(map[string]*State)
+	for _, s := range wf.States {
+		if _, ok := states[s.ID]; !ok {
+			states[s.ID] = s
+		}
+	}
+
+	seen := make(map[string]bool)
+	queue := []string{wf.StartState()}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		s := states[id]
+		if s == nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		for _, t := range s.Transitions {
+			queue = append(queue, t.To)
+		}
+	}
+
+	return seen
+}
@@ -0,0 +1,166 @@
+package definition
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		// want are the problems as 'LINE SEVERITY'
+		want []string
+	}{
+		{
+			name: "state without id and no start",
+			yaml: "id: x\nstates:\n- type: noop\n",
+			want: []string{"3 error"},
+		},
+		{
+			name: "start state not defined",
+			yaml: "id: x\nstart:\n  type: default\n  state: b\nstates:\n- id: a\n  type: noop\n",
+			want: []string{"3 error"},
+		},
+		{
+			name: "undefined transition",
+			yaml: "id: x\nstates:\n- id: a\n  type: noop\n  transition: b\n",
+			want: []string{"5 error"},
+		},
+		{
+			name: "no states",
+			yaml: "id: x\n",
+			want: []string{"1 error"},
+		},
+		{
+			name: "every problem",
+			yaml: `functions:
+- id: fn
+  image: a
+- id: fn
+  image: b
+states:
+- id: a
+  type: action
+  action:
+    function: nope
+  transition: ''
+- id: a
+  type: noop
+- id: c
+  type: noop
+`,
+			want: []string{
+				"1 error",    // no id
+				"2 warning",  // fn never called
+				"4 error",    // fn defined twice
+				"10 error",   // undefined function
+				"11 error",   // empty transition
+				"12 error",   // a defined twice
+				"14 warning", // c unreachable
+			},
+		},
+		{
+			name: "rejected by the model",
+			yaml: "id: x\nstates:\n- id: a\n  type: bogus\n",
+			want: []string{"3 error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := Parse([]byte(tt.yaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, p := range Validate(wf) {
+				got = append(got, fmt.Sprintf("%d %s", p.Line, p.Severity))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	wf, err := Parse([]byte(`id: x
+functions:
+- id: fn
+  image: vorteil/request
+states:
+- id: a
+  type: action
+  action:
+    function: fn
+    input: '.'
+  transform: '{ result: .return }'
+  transition: b
+- id: b
+  type: noop
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if problems := Validate(wf); len(problems) > 0 {
+		t.Fatalf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateLint(t *testing.T) {
+	wf, err := Parse([]byte(`id: x
+functions:
+- id: unused
+  image: vorteil/request
+states:
+- id: a
+  type: noop
+  transform: '.a |'
+- id: b
+  type: noop
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, p := range Validate(wf) {
+		got = append(got, fmt.Sprintf("%d %s", p.Line, p.Severity))
+	}
+
+	want := []string{"3 warning", "8 error", "9 warning"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestErrorLine(t *testing.T) {
+	wf, err := Parse([]byte(testWorkflow))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		msg  string
+		line int
+	}{
+		{"yaml: unmarshal errors:\n  line 7: cannot unmarshal", 7},
+		{"states[1]: id is required", 12},
+		{"states[9]: id is required", 1},
+		{"state 'wait': transition to undefined state 'nowhere'", 20},
+		{`function "fn" has no image`, 3},
+		{"state 'nowhere' does not exist", 1},
+		{"workflow id is required", 1},
+	}
+
+	for _, tt := range tests {
+		if line := errorLine(wf, tt.msg); line != tt.line {
+			t.Errorf("%q: got line %d, want %d", tt.msg, line, tt.line)
+		}
+	}
+}
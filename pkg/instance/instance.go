@@ -1,17 +1,21 @@
+// Package instance is deprecated, use the instance methods of client.Client
+// instead.
 package instance
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vorteil/direkcli/pkg/util"
 	"github.com/vorteil/direktiv/pkg/ingress"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 )
 
 // Logs returns all logs associated with the workflow instance ID
-func Logs(conn *grpc.ClientConn, id string) ([]*ingress.GetWorkflowInstanceLogsResponse_WorkflowInstanceLog, error) {
-	client, ctx, cancel := util.CreateClient(conn)
+//
+// Deprecated: use client.Client.InstanceLogs.
+func Logs(ctx context.Context, c *util.Client, id string) ([]*ingress.GetWorkflowInstanceLogsResponse_WorkflowInstanceLog, error) {
+	ctx, cancel := c.Context(ctx)
 	defer cancel()
 	offset := int32(0)
 	limit := int32(10000)
@@ -24,7 +28,7 @@ func Logs(conn *grpc.ClientConn, id string) ([]*ingress.GetWorkflowInstanceLogsR
 	}
 
 	// send grpc request
-	resp, err := client.GetWorkflowInstanceLogs(ctx, &request)
+	resp, err := c.Ingress().GetWorkflowInstanceLogs(ctx, &request)
 	if err != nil {
 		s := status.Convert(err)
 		return nil, fmt.Errorf("[%v] %v", s.Code(), s.Message())
@@ -34,8 +38,10 @@ func Logs(conn *grpc.ClientConn, id string) ([]*ingress.GetWorkflowInstanceLogsR
 }
 
 // List workflow instances
-func List(conn *grpc.ClientConn, namespace string) ([]*ingress.GetWorkflowInstancesResponse_WorkflowInstance, error) {
-	client, ctx, cancel := util.CreateClient(conn)
+//
+// Deprecated: use client.Client.ListInstances.
+func List(ctx context.Context, c *util.Client, namespace string) ([]*ingress.GetWorkflowInstancesResponse_WorkflowInstance, error) {
+	ctx, cancel := c.Context(ctx)
 	defer cancel()
 	// prepare request
 	request := ingress.GetWorkflowInstancesRequest{
@@ -43,7 +49,7 @@ func List(conn *grpc.ClientConn, namespace string) ([]*ingress.GetWorkflowInstan
 	}
 
 	// send grpc request
-	resp, err := client.GetWorkflowInstances(ctx, &request)
+	resp, err := c.Ingress().GetWorkflowInstances(ctx, &request)
 	if err != nil {
 		s := status.Convert(err)
 		return nil, fmt.Errorf("[%v] %v", s.Code(), s.Message())
@@ -53,8 +59,10 @@ func List(conn *grpc.ClientConn, namespace string) ([]*ingress.GetWorkflowInstan
 }
 
 // Get returns a workflow instance.
-func Get(conn *grpc.ClientConn, id string) (*ingress.GetWorkflowInstanceResponse, error) {
-	client, ctx, cancel := util.CreateClient(conn)
+//
+// Deprecated: use client.Client.GetInstance.
+func Get(ctx context.Context, c *util.Client, id string) (*ingress.GetWorkflowInstanceResponse, error) {
+	ctx, cancel := c.Context(ctx)
 	defer cancel()
 	// prepare request
 	request := ingress.GetWorkflowInstanceRequest{
@@ -62,7 +70,7 @@ func Get(conn *grpc.ClientConn, id string) (*ingress.GetWorkflowInstanceResponse
 	}
 
 	// send grpc request
-	resp, err := client.GetWorkflowInstance(ctx, &request)
+	resp, err := c.Ingress().GetWorkflowInstance(ctx, &request)
 	if err != nil {
 		s := status.Convert(err)
 		return nil, fmt.Errorf("[%v] %v", s.Code(), s.Message())
@@ -1,52 +1,47 @@
+// Package namespace is deprecated, use the namespace methods of
+// client.Client instead.
 package namespace
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 
 	"github.com/vorteil/direkcli/pkg/util"
 	"github.com/vorteil/direktiv/pkg/ingress"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 )
 
 // SendEvent sends the provided Cloud Event file to the specified namespace.
-func SendEvent(conn *grpc.ClientConn, namespace string, filepath string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
+//
+// Deprecated: use client.Client.SendEvent.
+func SendEvent(ctx context.Context, c *util.Client, namespace string, filepath string) (string, error) {
 	// read Cloud Event file
 	event, err := ioutil.ReadFile(filepath)
 	if err != nil {
 		return "", err
 	}
 
-	// prepare request
-	request := ingress.BroadcastEventRequest{
-		Namespace:  &namespace,
-		Cloudevent: event,
-	}
-
-	// send grpc request
-	_, err = client.BroadcastEvent(ctx, &request)
+	err = c.SendEvent(ctx, namespace, event)
 	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+		return "", err
 	}
 
 	return fmt.Sprintf("Successfully sent event to '%s'", namespace), nil
 }
 
 // List returns a list of namespaces
-func List(conn *grpc.ClientConn) ([]*ingress.GetNamespacesResponse_Namespace, error) {
-	client, ctx, cancel := util.CreateClient(conn)
+//
+// Deprecated: use client.Client.ListNamespaces.
+func List(ctx context.Context, c *util.Client) ([]*ingress.GetNamespacesResponse_Namespace, error) {
+	ctx, cancel := c.Context(ctx)
 	defer cancel()
 
 	// prepare request
 	request := ingress.GetNamespacesRequest{}
 
 	// send grpc request
-	resp, err := client.GetNamespaces(ctx, &request)
+	resp, err := c.Ingress().GetNamespaces(ctx, &request)
 	if err != nil {
 		s := status.Convert(err)
 		return nil, fmt.Errorf("[%v] %v", s.Code(), s.Message())
@@ -56,41 +51,25 @@ func List(conn *grpc.ClientConn) ([]*ingress.GetNamespacesResponse_Namespace, er
 }
 
 // Delete a namespace
-func Delete(name string, conn *grpc.ClientConn) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
-	// prepare request
-	request := ingress.DeleteNamespaceRequest{
-		Name: &name,
-	}
-
-	// send grpc request
-	resp, err := client.DeleteNamespace(ctx, &request)
+//
+// Deprecated: use client.Client.DeleteNamespace.
+func Delete(ctx context.Context, name string, c *util.Client) (string, error) {
+	err := c.DeleteNamespace(ctx, name)
 	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+		return "", err
 	}
 
-	return fmt.Sprintf("Deleted namespace: %s", resp.GetName()), nil
+	return fmt.Sprintf("Deleted namespace: %s", name), nil
 }
 
 // Create a new namespace
-func Create(name string, conn *grpc.ClientConn) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
-	// prepare request
-	request := ingress.AddNamespaceRequest{
-		Name: &name,
-	}
-
-	// send grpc request
-	resp, err := client.AddNamespace(ctx, &request)
+//
+// Deprecated: use client.Client.CreateNamespace.
+func Create(ctx context.Context, name string, c *util.Client) (string, error) {
+	ns, err := c.CreateNamespace(ctx, name)
 	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+		return "", err
 	}
 
-	return fmt.Sprintf("Created namespace: %s", resp.GetName()), nil
+	return fmt.Sprintf("Created namespace: %s", ns.Name), nil
 }
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffColors colours the lines of a unified diff by their prefix. Like the
+// level colours they are only used if the writer is a terminal.
+var diffColors = []struct {
+	prefix string
+	attrs  []color.Attribute
+}{
+	{"+++", []color.Attribute{color.Bold}},
+	{"---", []color.Attribute{color.Bold}},
+	{"@@", []color.Attribute{color.FgCyan}},
+	{"+", []color.Attribute{color.FgGreen}},
+	{"-", []color.Attribute{color.FgRed}},
+}
+
+// WriteDiff writes a unified diff from a to b with three lines of context,
+// reporting whether they differ. Definitions that differ only in trailing
+// whitespace are treated as equal.
+func WriteDiff(w io.Writer, a, b []byte, fromName, toName string) (bool, error) {
+	from := strings.TrimRight(string(a), " \t\r\n")
+	to := strings.TrimRight(string(b), " \t\r\n")
+	if from == to {
+		return false, nil
+	}
+
+	text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+	if err != nil {
+		return true, err
+	}
+
+	enabled := colorEnabled(w)
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+
+		for _, dc := range diffColors {
+			if strings.HasPrefix(line, dc.prefix) {
+				line = newColor(enabled, dc.attrs...).Sprint(strings.TrimSuffix(line, "\n")) + "\n"
+				break
+			}
+		}
+
+		_, err = fmt.Fprint(w, line)
+		if err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
+// splitLines splits s into lines that each end in a newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return difflib.SplitLines(s)
+}
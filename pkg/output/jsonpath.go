@@ -0,0 +1,282 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed template in the JSONPath dialect used by kubectl,
+// e.g. '{.workflowInstances[*].id}'. Text outside of braces is printed as is.
+// Supported expressions are field access (.name or ['name']), recursive
+// descent (..name), wildcards ([*] or .*), indexes ([n], negative from the
+// end) and quoted string literals ({"\n"}).
+type JSONPath struct {
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	text  string
+	path  []pathStep
+	isLit bool
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepRecursive
+	stepWildcard
+	stepIndex
+)
+
+type pathStep struct {
+	kind  stepKind
+	name  string
+	index int
+}
+
+// ParseJSONPath parses a JSONPath template.
+func ParseJSONPath(tmpl string) (*JSONPath, error) {
+	j := new(JSONPath)
+
+	for len(tmpl) > 0 {
+		open := strings.Index(tmpl, "{")
+		if open < 0 {
+			j.segments = append(j.segments, jsonPathSegment{text: tmpl, isLit: true})
+			break
+		}
+
+		if open > 0 {
+			j.segments = append(j.segments, jsonPathSegment{text: tmpl[:open], isLit: true})
+		}
+
+		end := strings.Index(tmpl[open:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed '{' in jsonpath template")
+		}
+		expr := strings.TrimSpace(tmpl[open+1 : open+end])
+		tmpl = tmpl[open+end+1:]
+
+		if strings.HasPrefix(expr, "\"") {
+			text, err := strconv.Unquote(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string literal %s in jsonpath template", expr)
+			}
+			j.segments = append(j.segments, jsonPathSegment{text: text, isLit: true})
+			continue
+		}
+
+		path, err := parsePath(expr)
+		if err != nil {
+			return nil, err
+		}
+		j.segments = append(j.segments, jsonPathSegment{path: path})
+	}
+
+	return j, nil
+}
+
+// Execute writes the template to w, evaluating expressions against data,
+// which must be the generic JSON representation of an object.
+func (j *JSONPath) Execute(w io.Writer, data interface{}) error {
+	for _, seg := range j.segments {
+		if seg.isLit {
+			_, err := io.WriteString(w, seg.text)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		s, err := formatValues(evalPath(seg.path, data))
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parsePath parses a single expression such as '.workflowInstances[*].id'.
+func parsePath(expr string) ([]pathStep, error) {
+	var steps []pathStep
+
+	expr = strings.TrimPrefix(expr, "$")
+	expr = strings.TrimPrefix(expr, "@")
+
+	for len(expr) > 0 {
+		switch {
+		case strings.HasPrefix(expr, ".."):
+			name, rest := splitName(expr[2:])
+			if name == "" {
+				return nil, fmt.Errorf("missing field name after '..' in jsonpath expression")
+			}
+			steps = append(steps, pathStep{kind: stepRecursive, name: name})
+			expr = rest
+
+		case expr[0] == '.':
+			name, rest := splitName(expr[1:])
+			if name == "*" {
+				steps = append(steps, pathStep{kind: stepWildcard})
+			} else if name != "" {
+				steps = append(steps, pathStep{kind: stepField, name: name})
+			}
+			expr = rest
+
+		case expr[0] == '[':
+			end := strings.Index(expr, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in jsonpath expression")
+			}
+			inner := strings.TrimSpace(expr[1:end])
+			expr = expr[end+1:]
+
+			if inner == "*" {
+				steps = append(steps, pathStep{kind: stepWildcard})
+				continue
+			}
+
+			if strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'") && len(inner) >= 2 {
+				steps = append(steps, pathStep{kind: stepField, name: inner[1 : len(inner)-1]})
+				continue
+			}
+
+			i, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("unsupported subscript '[%s]' in jsonpath expression", inner)
+			}
+			steps = append(steps, pathStep{kind: stepIndex, index: i})
+
+		default:
+			// allow the leading '.' to be omitted, e.g. 'id' for '.id'
+			name, rest := splitName(expr)
+			steps = append(steps, pathStep{kind: stepField, name: name})
+			expr = rest
+		}
+	}
+
+	return steps, nil
+}
+
+// splitName splits a field name from the remainder of an expression.
+func splitName(expr string) (string, string) {
+	i := strings.IndexAny(expr, ".[")
+	if i < 0 {
+		return expr, ""
+	}
+	return expr[:i], expr[i:]
+}
+
+// evalPath returns every value reached by following the steps from data.
+// Missing fields and out of range indexes yield no values.
+func evalPath(steps []pathStep, data interface{}) []interface{} {
+	values := []interface{}{data}
+
+	for _, step := range steps {
+		var next []interface{}
+
+		for _, v := range values {
+			switch step.kind {
+			case stepField:
+				if m, ok := v.(map[string]interface{}); ok {
+					if x, ok := m[step.name]; ok {
+						next = append(next, x)
+					}
+				}
+
+			case stepRecursive:
+				next = append(next, descend(step.name, v)...)
+
+			case stepWildcard:
+				switch x := v.(type) {
+				case []interface{}:
+					next = append(next, x...)
+				case map[string]interface{}:
+					for _, k := range sortedKeys(x) {
+						next = append(next, x[k])
+					}
+				}
+
+			case stepIndex:
+				if a, ok := v.([]interface{}); ok {
+					i := step.index
+					if i < 0 {
+						i += len(a)
+					}
+					if i >= 0 && i < len(a) {
+						next = append(next, a[i])
+					}
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+// descend returns the values of every field with the given name found in v
+// or any of its descendants.
+func descend(name string, v interface{}) []interface{} {
+	var out []interface{}
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if y, ok := x[name]; ok {
+			out = append(out, y)
+		}
+		for _, k := range sortedKeys(x) {
+			out = append(out, descend(name, x[k])...)
+		}
+	case []interface{}:
+		for _, y := range x {
+			out = append(out, descend(name, y)...)
+		}
+	}
+
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatValues joins values with spaces, printing strings and numbers as is
+// and anything else as JSON.
+func formatValues(values []interface{}) (string, error) {
+	parts := make([]string, len(values))
+
+	for i, v := range values {
+		switch x := v.(type) {
+		case string:
+			parts[i] = x
+		case float64:
+			parts[i] = strconv.FormatFloat(x, 'f', -1, 64)
+		case bool:
+			parts[i] = strconv.FormatBool(x)
+		default:
+			b, err := json.Marshal(x)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = string(b)
+		}
+	}
+
+	return strings.Join(parts, " "), nil
+}
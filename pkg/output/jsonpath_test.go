@@ -0,0 +1,240 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+  "namespace": "ns",
+  "workflowInstances": [
+    {"id": "ns/a/1", "status": "complete", "output": {"id": "nested"}},
+    {"id": "ns/b/2", "status": "failed", "attempts": 3}
+  ],
+  "labels": {"team": "ops", "env": "prod"},
+  "ready": true
+}`
+
+func testData(t *testing.T) interface{} {
+	t.Helper()
+
+	var v interface{}
+	err := json.Unmarshal([]byte(testDoc), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestJSONPath(t *testing.T) {
+	data := testData(t)
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"field", "{.namespace}", "ns"},
+		{"root", "{$.namespace}", "ns"},
+		{"implicit dot", "{namespace}", "ns"},
+		{"bracket field", "{['namespace']}", "ns"},
+		{"nested field", "{.labels.team}", "ops"},
+		{"bool", "{.ready}", "true"},
+		{"number", "{.workflowInstances[1].attempts}", "3"},
+		{"object", "{.workflowInstances[0].output}", `{"id":"nested"}`},
+		{"index", "{.workflowInstances[0].id}", "ns/a/1"},
+		{"negative index", "{.workflowInstances[-1].id}", "ns/b/2"},
+		{"wildcard", "{.workflowInstances[*].status}", "complete failed"},
+		{"dot wildcard", "{.labels.*}", "prod ops"},
+		{"recursive", "{..id}", "ns/a/1 nested ns/b/2"},
+		{"recursive below field", "{.workflowInstances[0]..id}", "ns/a/1 nested"},
+		{"text and literals", `ns={.namespace}{"\t"}{.labels.env}{"\n"}`, "ns=ns\tprod\n"},
+		{"missing field", "{.missing}", ""},
+		{"missing nested field", "{.workflowInstances[*].attempts}", "3"},
+		{"index out of range", "{.workflowInstances[5].id}", ""},
+		{"negative index out of range", "{.workflowInstances[-3].id}", ""},
+		{"index of object", "{.labels[0]}", ""},
+		{"field of array", "{.workflowInstances.id}", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := ParseJSONPath(tt.tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			err = j.Execute(&buf, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tt.want {
+				t.Fatalf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	tests := []string{
+		"{.namespace",
+		"{.items[0}",
+		"{.items[x]}",
+		"{..}",
+		`{"unterminated}`,
+	}
+
+	for _, tmpl := range tests {
+		t.Run(tmpl, func(t *testing.T) {
+			_, err := ParseJSONPath(tmpl)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestCustomColumns(t *testing.T) {
+	data := testData(t)
+	items := data.(map[string]interface{})["workflowInstances"].([]interface{})
+
+	tests := []struct {
+		name   string
+		spec   string
+		header []string
+		rows   [][]string
+	}{
+		{
+			name:   "fields",
+			spec:   "ID:.id,STATUS:.status",
+			header: []string{"ID", "STATUS"},
+			rows:   [][]string{{"ns/a/1", "complete"}, {"ns/b/2", "failed"}},
+		},
+		{
+			name:   "braces and missing values",
+			spec:   "ID:{.id},ATTEMPTS:{.attempts}",
+			header: []string{"ID", "ATTEMPTS"},
+			rows:   [][]string{{"ns/a/1", "<none>"}, {"ns/b/2", "3"}},
+		},
+		{
+			name:   "recursive",
+			spec:   "IDS:..id",
+			header: []string{"IDS"},
+			rows:   [][]string{{"ns/a/1 nested"}, {"ns/b/2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newCustomColumnsPrinter(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			err = p.Print(&buf, &Result{Items: items})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var lines [][]string
+			for _, l := range strings.Split(buf.String(), "\n") {
+				if fields := tableCells(l); len(fields) > 0 {
+					lines = append(lines, fields)
+				}
+			}
+
+			want := append([][]string{tt.header}, tt.rows...)
+			if len(lines) != len(want) {
+				t.Fatalf("got %d rows, want %d:\n%s", len(lines), len(want), buf.String())
+			}
+			for i := range want {
+				if strings.Join(lines[i], "|") != strings.Join(want[i], "|") {
+					t.Fatalf("row %d: got %q, want %q:\n%s", i, lines[i], want[i], buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCustomColumnsErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"ID",
+		"ID:",
+		":.id",
+		"ID:.id,STATUS",
+		"ID:.items[x]",
+	}
+
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			_, err := newCustomColumnsPrinter(spec)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+// tableCells splits a rendered table row into its trimmed cells, ignoring
+// borders.
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.Trim(line, "+-") == "" {
+		return nil
+	}
+
+	var cells []string
+	for _, c := range strings.Split(strings.Trim(line, "|"), "|") {
+		cells = append(cells, strings.TrimSpace(c))
+	}
+	return cells
+}
+
+func TestGoTemplate(t *testing.T) {
+	type instance struct {
+		ID string `json:"id"`
+	}
+
+	// fields are named as in JSON, not as in Go
+	p, err := NewPrinter("go-template={{range .workflowInstances}}{{.id}} {{end}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = p.Print(&buf, &Result{
+		Object: &struct {
+			WorkflowInstances []*instance `json:"workflowInstances"`
+		}{[]*instance{{"ns/a/1"}, {"ns/b/2"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "ns/a/1 ns/b/2 "; buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestGoTemplateMissingField(t *testing.T) {
+	p, err := NewPrinter("go-template={{.Id}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = p.Print(&buf, &Result{
+		Object: &struct {
+			ID string `json:"id"`
+		}{"x"},
+	})
+	if err == nil {
+		t.Fatalf("expected an error for a Go field name, got %q", buf.String())
+	}
+}
@@ -0,0 +1,164 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/vorteil/direkcli/client"
+)
+
+// Log formats supported by NewLogWriter.
+const (
+	LogFormatText   = "text"
+	LogFormatJSON   = "json"
+	LogFormatLogfmt = "logfmt"
+)
+
+// LogFormats lists the names accepted by NewLogWriter.
+var LogFormats = []string{LogFormatText, LogFormatJSON, LogFormatLogfmt}
+
+// timestampFormat is used for timestamps in the text and logfmt formats.
+const timestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// levelColors colours the level shown by the text format. Colours are only
+// used if the writer is a terminal and NO_COLOR is not set.
+var levelColors = map[string][]color.Attribute{
+	"debug": {color.FgCyan},
+	"info":  {color.FgGreen},
+	"warn":  {color.FgYellow},
+	"error": {color.FgRed},
+	"fatal": {color.FgRed, color.Bold},
+}
+
+// LogWriter writes instance log entries in one of the log formats.
+type LogWriter struct {
+	w          io.Writer
+	format     string
+	timestamps bool
+	colors     map[string]*color.Color
+}
+
+// NewLogWriter returns a writer for the named format. Timestamps are always
+// included by the json and logfmt formats, and only by the text format if
+// timestamps is set.
+func NewLogWriter(w io.Writer, format string, timestamps bool) (*LogWriter, error) {
+	switch format {
+	case "":
+		format = LogFormatText
+	case LogFormatText, LogFormatJSON, LogFormatLogfmt:
+	default:
+		return nil, fmt.Errorf("unknown log format '%s', expected one of: %s", format, strings.Join(LogFormats, ", "))
+	}
+
+	enabled := colorEnabled(w)
+	colors := make(map[string]*color.Color, len(levelColors))
+	for level, attrs := range levelColors {
+		colors[level] = newColor(enabled, attrs...)
+	}
+
+	return &LogWriter{
+		w:          w,
+		format:     format,
+		timestamps: timestamps,
+		colors:     colors,
+	}, nil
+}
+
+// Write writes a single entry on its own line.
+func (lw *LogWriter) Write(e *client.LogEntry) error {
+	var err error
+
+	switch lw.format {
+	case LogFormatJSON:
+		var b []byte
+		b, err = json.Marshal(e)
+		if err == nil {
+			_, err = fmt.Fprintln(lw.w, string(b))
+		}
+	case LogFormatLogfmt:
+		_, err = fmt.Fprintln(lw.w, logfmt(e))
+	default:
+		_, err = fmt.Fprintln(lw.w, lw.text(e))
+	}
+
+	return err
+}
+
+// text renders an entry as 'TIMESTAMP LEVEL MESSAGE', leaving out the
+// timestamp unless timestamps are enabled and the level if it has none.
+func (lw *LogWriter) text(e *client.LogEntry) string {
+	var parts []string
+
+	if lw.timestamps {
+		parts = append(parts, e.Timestamp.Format(timestampFormat))
+	}
+
+	if level := logLevel(e); level != "" {
+		label := strings.ToUpper(level)
+		if c, ok := lw.colors[level]; ok {
+			label = c.Sprint(label)
+		}
+		parts = append(parts, label)
+	}
+
+	parts = append(parts, strings.TrimRight(e.Message, "\n"))
+
+	return strings.Join(parts, " ")
+}
+
+// logLevel returns the lower case level stored in the context of an entry,
+// or an empty string if it has none.
+func logLevel(e *client.LogEntry) string {
+	for _, key := range []string{"level", "lvl"} {
+		if v, ok := e.Context[key]; ok {
+			v = strings.ToLower(v)
+			if v == "warning" {
+				v = "warn"
+			}
+			return v
+		}
+	}
+	return ""
+}
+
+// logfmt renders an entry as space separated key=value pairs, followed by
+// its context in alphabetical order.
+func logfmt(e *client.LogEntry) string {
+	pairs := []string{
+		"time=" + logfmtValue(e.Timestamp.Format(timestampFormat)),
+	}
+
+	if level := logLevel(e); level != "" {
+		pairs = append(pairs, "level="+logfmtValue(level))
+	}
+
+	pairs = append(pairs, "msg="+logfmtValue(strings.TrimRight(e.Message, "\n")))
+
+	keys := make([]string, 0, len(e.Context))
+	for k := range e.Context {
+		if k != "level" && k != "lvl" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+logfmtValue(e.Context[k]))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// logfmtValue quotes v if it is empty or contains spaces, quotes, equals
+// signs or control characters.
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \"=\t\r\n\\") {
+		return strconv.Quote(v)
+	}
+	return v
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/vorteil/direkcli/client"
+)
+
+func TestLogWriter(t *testing.T) {
+	// colours must follow the writer, not stdout
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	e := &client.LogEntry{
+		Timestamp: time.Date(2021, 2, 3, 4, 5, 6, 7000000, time.UTC),
+		Message:   "hello world\n",
+		Context:   map[string]string{"level": "Warning", "step": "1"},
+	}
+
+	tests := []struct {
+		format     string
+		timestamps bool
+		want       string
+	}{
+		{LogFormatText, false, "WARN hello world\n"},
+		{LogFormatText, true, "2021-02-03T04:05:06.007Z WARN hello world\n"},
+		{LogFormatLogfmt, false, "time=2021-02-03T04:05:06.007Z level=warn msg=\"hello world\" step=1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			lw, err := NewLogWriter(&buf, tt.format, tt.timestamps)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = lw.Write(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tt.want {
+				t.Fatalf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
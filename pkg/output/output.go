@@ -0,0 +1,224 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sisatech/tablewriter"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats supported by NewPrinter.
+const (
+	FormatTable = "table"
+	FormatWide  = "wide"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatName  = "name"
+)
+
+// Formats lists the names accepted by NewPrinter.
+var Formats = []string{FormatTable, FormatWide, FormatJSON, FormatYAML, FormatName,
+	FormatGoTemplate + "=...", FormatJSONPath + "=...", FormatCustomColumns + "=..."}
+
+// Column describes a single column of a table.
+type Column struct {
+	Header string
+	// Wide columns are only shown by the wide format.
+	Wide  bool
+	Value func(item interface{}) string
+}
+
+// Result is the output of a command, described so that it can be rendered in
+// any of the supported formats.
+type Result struct {
+	// Object is marshalled by the json and yaml formats.
+	Object interface{}
+	// Items are the rows rendered by the table formats.
+	Items   []interface{}
+	Columns []Column
+	// Name returns the identifier of an item for the name format.
+	Name func(item interface{}) string
+	// Text, if set, replaces the table for single objects.
+	Text func(w io.Writer) error
+	// List is the field of Object that holds Items, e.g. 'workflows'. It is
+	// needed to watch the result, so that updates keep the shape of Object.
+	List string
+}
+
+// Printer renders a result to a writer.
+type Printer interface {
+	Print(w io.Writer, r *Result) error
+	// Tabular reports whether the printer renders human readable tables
+	// rather than machine readable output.
+	Tabular() bool
+}
+
+// NewPrinter returns the printer for the named format. An empty format
+// selects the default table format. Template formats take their argument
+// after an equals sign, e.g. 'go-template={{.id}}'.
+func NewPrinter(format string) (Printer, error) {
+	if parts := strings.SplitN(format, "=", 2); len(parts) == 2 {
+		switch parts[0] {
+		case FormatGoTemplate:
+			return newGoTemplatePrinter(parts[1])
+		case FormatJSONPath:
+			return newJSONPathPrinter(parts[1])
+		case FormatCustomColumns:
+			return newCustomColumnsPrinter(parts[1])
+		}
+	}
+
+	switch format {
+	case "", FormatTable:
+		return &tablePrinter{}, nil
+	case FormatWide:
+		return &tablePrinter{wide: true}, nil
+	case FormatJSON:
+		return &jsonPrinter{}, nil
+	case FormatYAML:
+		return &yamlPrinter{}, nil
+	case FormatName:
+		return &namePrinter{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format '%s', expected one of: %s", format, strings.Join(Formats, ", "))
+}
+
+type tablePrinter struct {
+	wide bool
+}
+
+func (p *tablePrinter) Tabular() bool {
+	return true
+}
+
+func (p *tablePrinter) Print(w io.Writer, r *Result) error {
+	if r.Text != nil {
+		return r.Text(w)
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(p.header(r))
+
+	// Build string array rows
+	for _, item := range r.Items {
+		row, _ := p.row(r, item)
+		table.Append(row)
+	}
+
+	table.Render()
+	return nil
+}
+
+// columns returns the columns of r shown by the printer.
+func (p *tablePrinter) columns(r *Result) []Column {
+	var columns []Column
+	for _, c := range r.Columns {
+		if !c.Wide || p.wide {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+func (p *tablePrinter) header(r *Result) []string {
+	columns := p.columns(r)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Header
+	}
+	return header
+}
+
+func (p *tablePrinter) row(r *Result, item interface{}) ([]string, error) {
+	columns := p.columns(r)
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = c.Value(item)
+	}
+	return row, nil
+}
+
+type jsonPrinter struct{}
+
+func (p *jsonPrinter) Tabular() bool {
+	return false
+}
+
+func (p *jsonPrinter) Print(w io.Writer, r *Result) error {
+	b, err := MarshalJSON(r.Object)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+type yamlPrinter struct{}
+
+func (p *yamlPrinter) Tabular() bool {
+	return false
+}
+
+func (p *yamlPrinter) Print(w io.Writer, r *Result) error {
+	v, err := ToGeneric(r.Object)
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	err = enc.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+type namePrinter struct{}
+
+func (p *namePrinter) Tabular() bool {
+	return false
+}
+
+func (p *namePrinter) Print(w io.Writer, r *Result) error {
+	if r.Name == nil {
+		return fmt.Errorf("output format '%s' is not supported by this command", FormatName)
+	}
+
+	for _, item := range r.Items {
+		_, err := fmt.Fprintln(w, r.Name(item))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON marshals v as indented JSON.
+func MarshalJSON(v interface{}) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// ToGeneric converts v to the maps, slices and scalars it is represented by
+// in JSON.
+func ToGeneric(v interface{}) (interface{}, error) {
+	b, err := MarshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	err = json.Unmarshal(b, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/sisatech/tablewriter"
+)
+
+// Formats that take an argument, e.g. 'jsonpath={.id}'.
+const (
+	FormatGoTemplate    = "go-template"
+	FormatJSONPath      = "jsonpath"
+	FormatCustomColumns = "custom-columns"
+)
+
+type goTemplatePrinter struct {
+	tmpl *template.Template
+}
+
+// newGoTemplatePrinter parses the template so that referring to a field the
+// object does not have is an error rather than printing '<no value>'.
+func newGoTemplatePrinter(text string) (*goTemplatePrinter, error) {
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %v", err)
+	}
+
+	return &goTemplatePrinter{tmpl: tmpl}, nil
+}
+
+func (p *goTemplatePrinter) Tabular() bool {
+	return false
+}
+
+// Print executes the template against the JSON representation of the
+// object, so fields are referred to by their JSON names as with jsonpath,
+// e.g. '{{range .workflowInstances}}{{.id}}{{end}}'.
+func (p *goTemplatePrinter) Print(w io.Writer, r *Result) error {
+	v, err := ToGeneric(r.Object)
+	if err != nil {
+		return err
+	}
+
+	err = p.tmpl.Execute(w, v)
+	if err != nil {
+		return fmt.Errorf("go-template: %v, fields are referred to by their JSON names", err)
+	}
+	return nil
+}
+
+type jsonPathPrinter struct {
+	path *JSONPath
+}
+
+func newJSONPathPrinter(text string) (*jsonPathPrinter, error) {
+	path, err := ParseJSONPath(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonPathPrinter{path: path}, nil
+}
+
+func (p *jsonPathPrinter) Tabular() bool {
+	return false
+}
+
+// Print evaluates the template against the JSON representation of the
+// object, so fields are referred to by their JSON names.
+func (p *jsonPathPrinter) Print(w io.Writer, r *Result) error {
+	v, err := ToGeneric(r.Object)
+	if err != nil {
+		return err
+	}
+
+	return p.path.Execute(w, v)
+}
+
+type customColumn struct {
+	header string
+	path   []pathStep
+}
+
+type customColumnsPrinter struct {
+	columns []customColumn
+}
+
+// newCustomColumnsPrinter parses a comma separated list of HEADER:PATH pairs,
+// e.g. 'ID:.id,STATUS:.status'.
+func newCustomColumnsPrinter(spec string) (*customColumnsPrinter, error) {
+	p := new(customColumnsPrinter)
+
+	for _, col := range strings.Split(spec, ",") {
+		parts := strings.SplitN(col, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid custom column '%s', expected HEADER:PATH", col)
+		}
+
+		expr := strings.TrimSuffix(strings.TrimPrefix(parts[1], "{"), "}")
+		path, err := parsePath(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		p.columns = append(p.columns, customColumn{
+			header: parts[0],
+			path:   path,
+		})
+	}
+
+	return p, nil
+}
+
+func (p *customColumnsPrinter) Tabular() bool {
+	return true
+}
+
+// Print evaluates each column against the JSON representation of every
+// item, showing '<none>' where a path does not match.
+func (p *customColumnsPrinter) Print(w io.Writer, r *Result) error {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(p.header(r))
+
+	for _, item := range r.Items {
+		row, err := p.row(r, item)
+		if err != nil {
+			return err
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+	return nil
+}
+
+func (p *customColumnsPrinter) header(r *Result) []string {
+	header := make([]string, len(p.columns))
+	for i, c := range p.columns {
+		header[i] = c.header
+	}
+	return header
+}
+
+func (p *customColumnsPrinter) row(r *Result, item interface{}) ([]string, error) {
+	v, err := ToGeneric(item)
+	if err != nil {
+		return nil, err
+	}
+
+	row := make([]string, len(p.columns))
+	for i, c := range p.columns {
+		values := evalPath(c.path, v)
+		if len(values) == 0 {
+			row[i] = "<none>"
+			continue
+		}
+
+		row[i], err = formatValues(values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return row, nil
+}
@@ -0,0 +1,197 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Watcher prints successive results of the same command, showing only the
+// items that are new, have changed or have been removed since the previous
+// result. Items are identified by the Name of the result, which must be set.
+type Watcher struct {
+	w       io.Writer
+	printer Printer
+	// seen holds every item printed so far and its JSON, by name.
+	seen   map[string]*watchedItem
+	header bool
+	widths []int
+}
+
+type watchedItem struct {
+	item interface{}
+	json string
+}
+
+// rowPrinter is implemented by the printers that render one row per item,
+// which the watcher aligns under a single header.
+type rowPrinter interface {
+	header(r *Result) []string
+	row(r *Result, item interface{}) ([]string, error)
+}
+
+// Deleted marks items that have been removed since the previous result.
+const Deleted = "DELETED"
+
+// NewWatcher returns a watcher that prints with p. The table formats print
+// one aligned row per item under a single header, with removed items marked
+// DELETED at the end of their last row. Any other format prints the changed
+// items in the shape of the whole result, followed by the removed items with
+// an 'event' field of DELETED, or DELETED after their names.
+func NewWatcher(w io.Writer, p Printer) *Watcher {
+	return &Watcher{
+		w:       w,
+		printer: p,
+		seen:    make(map[string]*watchedItem),
+	}
+}
+
+// Update prints the items of r that are new, have changed or are gone.
+func (wt *Watcher) Update(r *Result) error {
+	if r.Name == nil || r.List == "" {
+		return fmt.Errorf("watching is not supported by this command")
+	}
+
+	var changed []interface{}
+	current := make(map[string]bool)
+	for _, item := range r.Items {
+		b, err := MarshalJSON(item)
+		if err != nil {
+			return err
+		}
+
+		name := r.Name(item)
+		current[name] = true
+		if prev, ok := wt.seen[name]; ok && prev.json == string(b) {
+			continue
+		}
+		wt.seen[name] = &watchedItem{item: item, json: string(b)}
+		changed = append(changed, item)
+	}
+
+	var names []string
+	for name := range wt.seen {
+		if !current[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var deleted []interface{}
+	for _, name := range names {
+		deleted = append(deleted, wt.seen[name].item)
+		delete(wt.seen, name)
+	}
+
+	if rp, ok := wt.printer.(rowPrinter); ok {
+		return wt.updateRows(rp, r, changed, deleted)
+	}
+
+	if len(changed) > 0 {
+		err := wt.print(r, changed, "", r.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(deleted) > 0 {
+		return wt.print(r, deleted, Deleted, func(item interface{}) string {
+			return r.Name(item) + " " + Deleted
+		})
+	}
+
+	return nil
+}
+
+// print prints items with a printer that is not row based, as an object of
+// the same shape as the whole result.
+func (wt *Watcher) print(r *Result, items []interface{}, event string, name func(item interface{}) string) error {
+	list, err := ToGeneric(items)
+	if err != nil {
+		return err
+	}
+
+	obj := map[string]interface{}{
+		r.List: list,
+	}
+	if event != "" {
+		obj["event"] = event
+	}
+
+	return wt.printer.Print(wt.w, &Result{
+		Object:  obj,
+		Items:   items,
+		Columns: r.Columns,
+		Name:    name,
+		List:    r.List,
+	})
+}
+
+func (wt *Watcher) updateRows(rp rowPrinter, r *Result, changed, deleted []interface{}) error {
+	if len(changed) == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(changed)+len(deleted)+1)
+
+	if !wt.header {
+		header := rp.header(r)
+		for i := range header {
+			header[i] = strings.ToUpper(header[i])
+		}
+		rows = append(rows, header)
+		wt.header = true
+	}
+
+	for _, item := range changed {
+		row, err := rp.row(r, item)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	for _, item := range deleted {
+		row, err := rp.row(r, item)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, append(row, Deleted))
+	}
+
+	return wt.writeRows(rows)
+}
+
+// writeRows writes rows aligned to the widest cell seen in each column so
+// far, so that rows stay aligned across updates unless a column grows.
+func (wt *Watcher) writeRows(rows [][]string) error {
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(wt.widths) {
+				wt.widths = append(wt.widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > wt.widths[i] {
+				wt.widths[i] = n
+			}
+		}
+	}
+
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", wt.widths[i]-utf8.RuneCountInString(cell)+3))
+			}
+		}
+
+		_, err := fmt.Fprintln(wt.w, b.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+type watchItem struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func watchResult(items ...*watchItem) *Result {
+	r := &Result{
+		Object: &struct {
+			Items []*watchItem `json:"items"`
+		}{items},
+		Columns: []Column{
+			{Header: "ID", Value: func(item interface{}) string {
+				return item.(*watchItem).ID
+			}},
+			{Header: "Status", Value: func(item interface{}) string {
+				return item.(*watchItem).Status
+			}},
+		},
+		Name: func(item interface{}) string {
+			return item.(*watchItem).ID
+		},
+		List: "items",
+	}
+
+	for _, item := range items {
+		r.Items = append(r.Items, item)
+	}
+
+	return r
+}
+
+func TestWatcher(t *testing.T) {
+	updates := []*Result{
+		watchResult(&watchItem{"a", "pending"}, &watchItem{"b", "pending"}),
+		watchResult(&watchItem{"a", "pending"}, &watchItem{"b", "complete"}),
+		watchResult(&watchItem{"b", "complete"}),
+		watchResult(&watchItem{"b", "complete"}),
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatTable, `ID   STATUS
+a    pending
+b    pending
+b    complete
+a    pending    DELETED
+`},
+		{"custom-columns=NAME:.id,STATE:.status", `NAME   STATE
+a      pending
+b      pending
+b      complete
+a      pending    DELETED
+`},
+		{"go-template={{range .items}}{{.id}}={{.status}}{{with $.event}} {{.}}{{end}}{{\"\\n\"}}{{end}}", `a=pending
+b=pending
+b=complete
+a=pending DELETED
+`},
+		{`jsonpath={.items[*].id}{"\n"}`, "a b\nb\na\n"},
+		{FormatName, `a
+b
+b
+a DELETED
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			p, err := NewPrinter(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			w := NewWatcher(&buf, p)
+			for _, r := range updates {
+				err = w.Update(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if buf.String() != tt.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
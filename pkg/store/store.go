@@ -1,23 +1,32 @@
+// Package registries is deprecated, use the secret and registry methods of
+// client.Client instead.
 package registries
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vorteil/direkcli/pkg/util"
 	"github.com/vorteil/direktiv/pkg/ingress"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 )
 
+// StoreRequest is a secret or registry to create.
+//
+// Deprecated: use client.Client.CreateSecret or client.Client.CreateRegistry.
 type StoreRequest struct {
 	Key   string
 	Value string
 }
 
-func List(conn *grpc.ClientConn, namespace string, typeOf string) (interface{}, error) {
+// List returns the secrets or registries of a namespace, as selected by
+// typeOf.
+//
+// Deprecated: use client.Client.ListSecrets or client.Client.ListRegistries.
+func List(ctx context.Context, c *util.Client, namespace string, typeOf string) (interface{}, error) {
 	var ifc interface{}
 
-	client, ctx, cancel := util.CreateClient(conn)
+	ctx, cancel := c.Context(ctx)
 	defer cancel()
 	switch typeOf {
 	case "secret":
@@ -27,7 +36,7 @@ func List(conn *grpc.ClientConn, namespace string, typeOf string) (interface{},
 		}
 
 		// send grpc request
-		resp, err := client.GetSecrets(ctx, &request)
+		resp, err := c.Ingress().GetSecrets(ctx, &request)
 		if err != nil {
 			s := status.Convert(err)
 			return nil, fmt.Errorf("[%v] %v", s.Code(), s.Message())
@@ -43,7 +52,7 @@ func List(conn *grpc.ClientConn, namespace string, typeOf string) (interface{},
 		}
 
 		// send grpc request
-		resp, err := client.GetRegistries(ctx, &request)
+		resp, err := c.Ingress().GetRegistries(ctx, &request)
 		if err != nil {
 			s := status.Convert(err)
 			return nil, fmt.Errorf("[%v] %v", s.Code(), s.Message())
@@ -54,96 +63,48 @@ func List(conn *grpc.ClientConn, namespace string, typeOf string) (interface{},
 	return ifc, nil
 }
 
-func Delete(conn *grpc.ClientConn, namespace string, secret string, typeOf string) (string, error) {
-	var success string
-	var err error
-
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
+// Delete removes a secret or registry, as selected by typeOf.
+//
+// Deprecated: use client.Client.DeleteSecret or client.Client.DeleteRegistry.
+func Delete(ctx context.Context, c *util.Client, namespace string, secret string, typeOf string) (string, error) {
 	switch typeOf {
 	case "secret":
-
-		// prepare request
-		request := ingress.DeleteSecretRequest{
-			Namespace: &namespace,
-			Name:      &secret,
-		}
-
-		// send grpc request
-		_, err := client.DeleteSecret(ctx, &request)
+		err := c.DeleteSecret(ctx, namespace, secret)
 		if err != nil {
-			s := status.Convert(err)
-			return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+			return "", err
 		}
-		success = fmt.Sprintf("Successfully removed secret '%s'.", secret)
+		return fmt.Sprintf("Successfully removed secret '%s'.", secret), nil
 
 	case "registry":
-
-		// prepare request
-		request := ingress.DeleteRegistryRequest{
-			Namespace: &namespace,
-			Name:      &secret,
-		}
-
-		// send grpc request
-		_, err := client.DeleteRegistry(ctx, &request)
+		err := c.DeleteRegistry(ctx, namespace, secret)
 		if err != nil {
-			s := status.Convert(err)
-			return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+			return "", err
 		}
-
-		success = fmt.Sprintf("Successfully removed registry '%s'.", secret)
+		return fmt.Sprintf("Successfully removed registry '%s'.", secret), nil
 	}
-	return success, err
-}
-
-func Create(conn *grpc.ClientConn, namespace string, s *StoreRequest, typeOf string) (string, error) {
 
-	var success string
-	var err error
-
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
+	return "", nil
+}
 
+// Create stores a secret or registry, as selected by typeOf.
+//
+// Deprecated: use client.Client.CreateSecret or client.Client.CreateRegistry.
+func Create(ctx context.Context, c *util.Client, namespace string, s *StoreRequest, typeOf string) (string, error) {
 	switch typeOf {
-
 	case "secret":
-
-		// prepare request
-		request := ingress.StoreSecretRequest{
-			Namespace: &namespace,
-			Name:      &s.Key,
-			Data:      []byte(s.Value),
-		}
-
-		// send grpc request
-		_, err := client.StoreSecret(ctx, &request)
+		err := c.CreateSecret(ctx, namespace, s.Key, []byte(s.Value))
 		if err != nil {
-			s := status.Convert(err)
-			return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+			return "", err
 		}
-
-		success = fmt.Sprintf("Successfully create secret '%s'.", s.Key)
+		return fmt.Sprintf("Successfully create secret '%s'.", s.Key), nil
 
 	case "registry":
-
-		// prepare request
-		request := ingress.StoreRegistryRequest{
-			Namespace: &namespace,
-			Name:      &s.Key,
-			Data:      []byte(s.Value),
-		}
-
-		// send grpc request
-		_, err := client.StoreRegistry(ctx, &request)
+		err := c.CreateRegistry(ctx, namespace, s.Key, s.Value)
 		if err != nil {
-			s := status.Convert(err)
-			return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+			return "", err
 		}
-
-		success = fmt.Sprintf("Successfully created registry '%s'.", s.Key)
+		return fmt.Sprintf("Successfully created registry '%s'.", s.Key), nil
 	}
 
-	return success, err
+	return "", nil
 }
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"crypto/tls"
+
+	"github.com/vorteil/direkcli/client"
+	"github.com/vorteil/direkcli/pkg/config"
+	"google.golang.org/grpc"
+)
+
+// Dial opens a connection to the direktiv server described by the profile.
+//
+// Deprecated: use config.Profile.ClientConfig and client.Dial.
+func Dial(p *config.Profile) (*Client, error) {
+	cfg, err := p.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.Dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{c}, nil
+}
+
+// DialOptions returns the transport and authentication options needed to
+// connect to the direktiv server described by the profile.
+//
+// Deprecated: use config.Profile.ClientConfig and client.DialOptions.
+func DialOptions(p *config.Profile) ([]grpc.DialOption, error) {
+	cfg, err := p.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.DialOptions(cfg)
+}
+
+// TLSConfig builds the TLS configuration for the profile, loading the CA
+// certificate and client key pair from disk if they are set.
+//
+// Deprecated: use config.Profile.ClientConfig and client.TLSConfig.
+func TLSConfig(p *config.Profile) (*tls.Config, error) {
+	cfg, err := p.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.TLSConfig(cfg)
+}
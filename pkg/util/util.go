@@ -1,24 +1,36 @@
+// Package util is deprecated, use package client instead. It is kept so that
+// existing importers keep building, and delegates to client.Client.
 package util
 
 import (
 	"context"
 	"time"
 
-	"github.com/vorteil/direktiv/pkg/ingress"
+	"github.com/vorteil/direkcli/client"
 	"google.golang.org/grpc"
 )
 
-func CreateClient(conn *grpc.ClientConn) (ingress.DirektivIngressClient, context.Context, context.CancelFunc) {
-	client := ingress.NewDirektivIngressClient(conn)
+// Client wraps a client.Client for the deprecated packages.
+//
+// Deprecated: use client.Client.
+type Client struct {
+	*client.Client
+}
 
-	// set context with 3 second timeout
-	ctx := context.Background()
-	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(time.Second*3))
+// NewClient returns a client using conn, applying the timeout to each call.
+//
+// Deprecated: use client.New.
+func NewClient(conn *grpc.ClientConn, timeout time.Duration) *Client {
+	return &Client{client.New(conn, timeout)}
+}
 
-	cancelConns := func() {
-		conn.Close()
-		cancel()
+// Context returns a context for a single call derived from ctx, with the
+// client's timeout applied.
+//
+// Deprecated: the methods of client.Client apply the timeout themselves.
+func (c *Client) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
-
-	return client, ctx, cancelConns
+	return context.WithTimeout(ctx, c.Timeout)
 }
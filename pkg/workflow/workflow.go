@@ -1,46 +1,27 @@
+// Package workflow is deprecated, use the workflow methods of client.Client
+// instead.
 package workflow
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 
 	"github.com/vorteil/direkcli/pkg/util"
 	"github.com/vorteil/direktiv/pkg/ingress"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 )
 
 // Toggle enables or disables the workflow
-func Toggle(conn *grpc.ClientConn, namespace, workflow string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
-	request := ingress.GetWorkflowByIdRequest{
-		Namespace: &namespace,
-		Id:        &workflow,
-	}
-
-	resp, err := client.GetWorkflowById(ctx, &request)
-	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
-	}
-
-	toggle := !*resp.Active
-
-	uRequest := ingress.UpdateWorkflowRequest{
-		Uid:      resp.Uid,
-		Workflow: resp.Workflow,
-		Active:   &toggle,
-	}
-
-	_, err = client.UpdateWorkflow(ctx, &uRequest)
+//
+// Deprecated: use client.Client.ToggleWorkflow.
+func Toggle(ctx context.Context, c *util.Client, namespace, workflow string) (string, error) {
+	wf, err := c.ToggleWorkflow(ctx, namespace, workflow)
 	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+		return "", err
 	}
 
-	if toggle {
+	if wf.Active {
 		return fmt.Sprintf("Enabled workflow '%s'", workflow), nil
 	}
 
@@ -48,8 +29,10 @@ func Toggle(conn *grpc.ClientConn, namespace, workflow string) (string, error) {
 }
 
 // List returns an array of workflows for a given namespace
-func List(conn *grpc.ClientConn, namespace string) ([]*ingress.GetWorkflowsResponse_Workflow, error) {
-	client, ctx, cancel := util.CreateClient(conn)
+//
+// Deprecated: use client.Client.ListWorkflows.
+func List(ctx context.Context, c *util.Client, namespace string) ([]*ingress.GetWorkflowsResponse_Workflow, error) {
+	ctx, cancel := c.Context(ctx)
 	defer cancel()
 
 	// prepare request
@@ -58,7 +41,7 @@ func List(conn *grpc.ClientConn, namespace string) ([]*ingress.GetWorkflowsRespo
 	}
 
 	// send grpc request
-	resp, err := client.GetWorkflows(ctx, &request)
+	resp, err := c.Ingress().GetWorkflows(ctx, &request)
 	if err != nil {
 		s := status.Convert(err)
 		return nil, fmt.Errorf("[%v] %v", s.Code(), s.Message())
@@ -68,10 +51,9 @@ func List(conn *grpc.ClientConn, namespace string) ([]*ingress.GetWorkflowsRespo
 }
 
 // Execute a workflow using the yaml provided
-func Execute(conn *grpc.ClientConn, namespace string, id string, input string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
+//
+// Deprecated: use client.Client.InvokeWorkflow.
+func Execute(ctx context.Context, c *util.Client, namespace string, id string, input string) (string, error) {
 	var err error
 	var b []byte
 	if input != "" {
@@ -81,141 +63,68 @@ func Execute(conn *grpc.ClientConn, namespace string, id string, input string) (
 		}
 	}
 
-	// prepare request
-	request := ingress.InvokeWorkflowRequest{
-		Namespace:  &namespace,
-		Input:      b,
-		WorkflowId: &id,
-	}
-
-	// send grpc request
-	resp, err := client.InvokeWorkflow(ctx, &request)
+	instanceID, err := c.InvokeWorkflow(ctx, namespace, id, b)
 	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
-	}
-
-	return fmt.Sprintf("Successfully invoked, Instance ID: %s", resp.GetInstanceId()), nil
-}
-
-// getWorkflowUID returns the UID of a workflow
-func getWorkflowUID(conn *grpc.ClientConn, namespace, id string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-	// prepare request
-	request := ingress.GetWorkflowByIdRequest{
-		Namespace: &namespace,
-		Id:        &id,
+		return "", err
 	}
 
-	// send grpc request
-	resp, err := client.GetWorkflowById(ctx, &request)
-	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
-	}
-	return resp.GetUid(), nil
+	return fmt.Sprintf("Successfully invoked, Instance ID: %s", instanceID), nil
 }
 
 // Get returns a workflow definition in YAML format
-func Get(conn *grpc.ClientConn, namespace string, id string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
-	// prepare request
-	request := ingress.GetWorkflowByIdRequest{
-		Namespace: &namespace,
-		Id:        &id,
-	}
-
-	// send grpc request
-	resp, err := client.GetWorkflowById(ctx, &request)
+//
+// Deprecated: use client.Client.GetWorkflow.
+func Get(ctx context.Context, c *util.Client, namespace string, id string) (string, error) {
+	wf, err := c.GetWorkflow(ctx, namespace, id)
 	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+		return "", err
 	}
 
-	return string(resp.GetWorkflow()), nil
+	return string(wf.Definition), nil
 }
 
 // Update a workflow specified by ID.
-func Update(conn *grpc.ClientConn, namespace string, id string, filepath string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
+//
+// Deprecated: use client.Client.UpdateWorkflow.
+func Update(ctx context.Context, c *util.Client, namespace string, id string, filepath string) (string, error) {
 	b, err := ioutil.ReadFile(filepath)
 	if err != nil {
 		return "", err
 	}
 
-	uid, err := getWorkflowUID(conn, namespace, id)
+	wf, err := c.UpdateWorkflow(ctx, namespace, id, b)
 	if err != nil {
 		return "", err
 	}
 
-	// prepare request
-	request := ingress.UpdateWorkflowRequest{
-		Uid:      &uid,
-		Workflow: b,
-	}
-
-	// send grpc request
-	resp, err := client.UpdateWorkflow(ctx, &request)
-	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
-	}
-
-	return fmt.Sprintf("Successfully updated '%s'", resp.GetId()), nil
+	return fmt.Sprintf("Successfully updated '%s'", wf.ID), nil
 }
 
 // Delete an existing workflow.
-func Delete(conn *grpc.ClientConn, namespace, id string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
-	uid, err := getWorkflowUID(conn, namespace, id)
+//
+// Deprecated: use client.Client.DeleteWorkflow.
+func Delete(ctx context.Context, c *util.Client, namespace, id string) (string, error) {
+	err := c.DeleteWorkflow(ctx, namespace, id)
 	if err != nil {
 		return "", err
 	}
 
-	// prepare request
-	request := ingress.DeleteWorkflowRequest{
-		Uid: &uid,
-	}
-
-	// send grpc request
-	_, err = client.DeleteWorkflow(ctx, &request)
-	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
-	}
-
 	return fmt.Sprintf("Deleted workflow '%v'", id), nil
 }
 
 // Add creates a new workflow
-func Add(conn *grpc.ClientConn, namespace string, filepath string) (string, error) {
-	client, ctx, cancel := util.CreateClient(conn)
-	defer cancel()
-
+//
+// Deprecated: use client.Client.CreateWorkflow.
+func Add(ctx context.Context, c *util.Client, namespace string, filepath string) (string, error) {
 	b, err := ioutil.ReadFile(filepath)
 	if err != nil {
 		return "", err
 	}
 
-	// prepare request
-	request := ingress.AddWorkflowRequest{
-		Namespace: &namespace,
-		Workflow:  b,
-	}
-
-	// send grpc request
-	resp, err := client.AddWorkflow(ctx, &request)
+	wf, err := c.CreateWorkflow(ctx, namespace, b)
 	if err != nil {
-		s := status.Convert(err)
-		return "", fmt.Errorf("[%v] %v", s.Code(), s.Message())
+		return "", err
 	}
 
-	return fmt.Sprintf("Created workflow '%s'", resp.GetId()), nil
+	return fmt.Sprintf("Created workflow '%s'", wf.ID), nil
 }